@@ -1,97 +1,134 @@
 package main
 
 import (
-	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/zarazaex69/zik/apps/cli/internal/ai"
-	"github.com/zarazaex69/zik/apps/cli/internal/config"
 	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
-	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
 )
 
 var (
 	askStream bool
+	askFiles  []string
+	askForce  bool
 
 	askCmd = &cobra.Command{
-		Use:   "ask <question>",
+		Use:   "ask [question]",
 		Short: "Ask a quick question to AI",
 		Long: `Ask a one-off question to the AI without maintaining conversation context.
-Perfect for quick queries, code explanations, or getting instant answers.`,
+Perfect for quick queries, code explanations, or getting instant answers.
+
+Input piped on stdin is attached to the question, or used as the question when none is given.
+Files passed with --file are attached as fenced code blocks.`,
 		Example: `  zik ask "What is the difference between let and const?"
   zik ask "How do I reverse a string in Go?"
-  zik ask --stream "Explain async/await in JavaScript"`,
-		Args: cobra.MinimumNArgs(1),
-		RunE: runAsk,
+  zik ask --stream "Explain async/await in JavaScript"
+  go test ./... 2>&1 | zik ask "why does this fail"
+  zik ask -f main.go -f go.mod "what does this program do"`,
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE:         runAsk,
 	}
 )
 
 func init() {
 	askCmd.Flags().BoolVarP(&askStream, "stream", "s", true, "Stream the response in real-time")
+	askCmd.Flags().StringArrayVarP(&askFiles, "file", "f", nil, "Attach a file (repeatable)")
+	askCmd.Flags().BoolVar(&askForce, "force", false, "Send the input even if it exceeds the context window")
+	bindConfigFlag(askCmd.Flags(), "stream", "streaming")
 }
 
 func runAsk(cmd *cobra.Command, args []string) error {
-	question := strings.Join(args, " ")
+	question := strings.TrimSpace(strings.Join(args, " "))
+
+	stdin, err := readPipedStdin(question == "")
+	if err != nil {
+		return err
+	}
+
+	// Piped input alone is the question itself
+	if question == "" {
+		question, stdin = strings.TrimSpace(stdin), ""
+	}
+	if question == "" {
+		return fmt.Errorf("no question given; pass it as an argument or pipe it on stdin")
+	}
+
+	var blocks []string
+	for _, path := range askFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !utf8.Valid(data) {
+			return fmt.Errorf("%s looks like a binary file", path)
+		}
+		blocks = append(blocks, prompt.FileBlock(path, string(data)))
+	}
+	if strings.TrimSpace(stdin) != "" {
+		blocks = append(blocks, prompt.StdinBlock(stdin))
+	}
+
+	content := question
+	if len(blocks) > 0 {
+		content = strings.Join(blocks, "\n\n") + "\n\n" + question
+	}
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := loadConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Guard against accidentally sending huge logs or files
+	size, budget := tokens.Estimate(content), inputBudget(cfg)
+	switch {
+	case size > budget && !askForce:
+		return fmt.Errorf("input is ~%d tokens but only ~%d fit the context window; trim it or pass --force", size, budget)
+	case size > budget/2:
+		fmt.Fprintf(os.Stderr, "Warning: sending a large payload (~%d tokens)\n", size)
+	}
+
 	// Initialize AI client
-	aiClient := ai.NewClient(cfg)
-	ctx := context.Background()
+	aiClient := newAIClient(cfg)
+	ctx, cancel := interruptContext(cmd.Context())
+	defer cancel()
 
 	// Build messages with system prompt to constrain formatting
 	messages := []ai.Message{
-		{Role: "system", Content: prompt.AskSystemPrompt()},
-		{Role: "user", Content: question},
+		{Role: "system", Content: prompt.WithInstructions(prompt.AskSystemPrompt(), cfg.Instructions)},
+		{Role: "user", Content: content},
 	}
 
-	if askStream {
-		// Streaming response with markdown rendering
-		renderer := render.NewMarkdownRenderer()
-		chunkChan, errChan := aiClient.ChatStream(ctx, messages, cfg.Temperature, cfg.MaxTokens)
-
-		for {
-			select {
-			case chunk, ok := <-chunkChan:
-				if !ok {
-					// Flush any remaining buffered content
-					if remaining := renderer.Flush(); remaining != "" {
-						fmt.Print(remaining)
-					}
-					fmt.Println() // New line at end
-					return nil
-				}
-				if chunk.Content != "" {
-					formatted := renderer.ProcessChunk(chunk.Content)
-					fmt.Print(formatted)
-				}
-			case err := <-errChan:
-				if err != nil {
-					return fmt.Errorf("AI request failed: %w", err)
-				}
-			}
-		}
-	} else {
-		// Non-streaming response with markdown rendering
-		resp, err := aiClient.Chat(ctx, messages, cfg.Temperature, cfg.MaxTokens)
-		if err != nil {
-			return fmt.Errorf("AI request failed: %w", err)
-		}
+	_, err = printReply(ctx, aiClient, messages, cfg)
+	return err
+}
 
-		if len(resp.Choices) == 0 {
-			return fmt.Errorf("no response from AI")
-		}
+// readPipedStdin returns everything piped into the command, or "" when stdin is a terminal
+// Unless required, only pipes and redirected files are read: other inputs, such as a socket
+// a CI runner leaves open, may never reach EOF and would hang a question given as an argument
+func readPipedStdin(required bool) (string, error) {
+	if isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return "", nil
+	}
 
-		renderer := render.NewMarkdownRenderer()
-		formatted := renderer.ProcessChunk(resp.Choices[0].Message.Content)
-		fmt.Println(formatted)
+	if !required {
+		info, err := os.Stdin.Stat()
+		if err != nil || (info.Mode()&os.ModeNamedPipe == 0 && !info.Mode().IsRegular()) {
+			return "", nil
+		}
 	}
 
-	return nil
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return string(data), nil
 }
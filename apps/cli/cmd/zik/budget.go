@@ -0,0 +1,26 @@
+package main
+
+import "github.com/zarazaex69/zik/apps/cli/internal/config"
+
+// minShareTokens is the smallest share of a budget a single file or change is truncated to
+const minShareTokens = 64
+
+// inputBudget returns the tokens a single request may spend on input, leaving room for prompts and the reply
+func inputBudget(cfg *config.Config) int {
+	budget := cfg.Chat.ContextTokens
+	if budget <= 0 {
+		budget = config.Default().Chat.ContextTokens
+	}
+
+	// Reserve space for the system prompt and the reply itself
+	available := budget - cfg.MaxTokens - 1000
+	if available < 1000 {
+		available = 1000
+	}
+	return available
+}
+
+// shareBudget splits budget evenly between n parts, giving each at least minShareTokens
+func shareBudget(budget, n int) int {
+	return max(budget/max(n, 1), minShareTokens)
+}
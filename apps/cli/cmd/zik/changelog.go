@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/changelog"
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
+	"github.com/zarazaex69/zik/apps/cli/internal/diff"
+	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+)
+
+var (
+	changelogFrom   string
+	changelogTo     string
+	changelogUpdate string
+
+	changelogCmd = &cobra.Command{
+		Use:   "changelog",
+		Short: "Write release notes and suggest the next version",
+		Long: `Write release notes for a range of commits and suggest the next semantic version.
+Conventional Commit messages are grouped by type, breaking changes first, and the AI rewrites
+them as user-facing notes in Keep a Changelog format. The suggested version bumps the major
+version for breaking changes, the minor version for features and the patch version otherwise.`,
+		Example: `  zik changelog                              # Since the latest tag
+  zik changelog --from v0.1.0 --to HEAD      # An explicit range
+  zik changelog --update CHANGELOG.md        # Prepend the release to a changelog`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runChangelog,
+	}
+)
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "Start of the range, exclusive (default: latest tag)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "HEAD", "End of the range")
+	changelogCmd.Flags().StringVarP(&changelogUpdate, "update", "u", "", "Prepend the release to a Keep a Changelog file instead of printing it")
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitClient := git.NewClient()
+	if !gitClient.IsRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	from := changelogFrom
+	if from == "" {
+		if from, err = gitClient.LatestTag(changelogTo); err != nil {
+			fmt.Fprintf(os.Stderr, "No tags found, using the whole history of %s\n", changelogTo)
+		}
+	}
+
+	revRange := changelogTo
+	if from != "" {
+		revRange = from + ".." + changelogTo
+	}
+	commits, err := gitClient.GetLogRange(revRange)
+	if err != nil {
+		return fmt.Errorf("failed to get git log: %w", err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits in %s", revRange)
+	}
+
+	groups := changelog.Collect(commits)
+	bump := changelog.Recommend(groups)
+
+	// Only a semantic version tag tells where to bump from
+	version := ""
+	if from != "" {
+		if next, err := changelog.Next(from, bump); err == nil {
+			version = next
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Writing release notes for %d commits in %s...\n", len(commits), revRange)
+
+	messages := []ai.Message{
+		{Role: "system", Content: prompt.WithInstructions(prompt.ChangelogSystemPrompt(), cfg.Instructions)},
+		{Role: "user", Content: prompt.ChangelogUserPrompt(revRange, diff.Truncate(changelog.Format(groups), inputBudget(cfg)))},
+	}
+
+	ctx, cancel := interruptContext(cmd.Context())
+	defer cancel()
+
+	aiClient := newAIClient(cfg)
+	resp, err := aiClient.Chat(ctx, messages, 0.3, cfg.MaxTokens)
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no response from AI")
+	}
+
+	notes := commitmsg.StripWrapping(resp.Choices[0].Message.Content)
+	if notes == "" {
+		return fmt.Errorf("AI returned empty release notes")
+	}
+	section := changelog.Section(version, time.Now().Format("2006-01-02"), notes)
+
+	if changelogUpdate == "" {
+		fmt.Print(section)
+	} else {
+		existing, err := os.ReadFile(changelogUpdate)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", changelogUpdate, err)
+		}
+		if err := os.WriteFile(changelogUpdate, []byte(changelog.Prepend(string(existing), section)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", changelogUpdate, err)
+		}
+		fmt.Fprintf(os.Stderr, "Updated %s\n", changelogUpdate)
+	}
+
+	switch {
+	case version != "":
+		fmt.Fprintf(os.Stderr, "Suggested next version: %s (%s bump from %s)\n", version, bump, from)
+	case from == "":
+		fmt.Fprintf(os.Stderr, "Suggested bump: %s (no tag to bump from)\n", bump)
+	default:
+		fmt.Fprintf(os.Stderr, "Suggested bump: %s (%s is not a semantic version)\n", bump, from)
+	}
+	return nil
+}
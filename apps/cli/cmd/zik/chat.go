@@ -1,20 +1,298 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/history"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/session"
+	"github.com/zarazaex69/zik/apps/cli/internal/slash"
 )
 
+// truncatedMarker ends replies cut short by Ctrl-C or the chat timeout in the history
+const truncatedMarker = "[truncated]"
+
 var (
+	chatResume   string
+	chatContinue bool
+
 	chatCmd = &cobra.Command{
 		Use:   "chat",
 		Short: "Start an interactive chat session with AI",
 		Long: `Start an interactive chat session with AI.
-Maintains conversation context and allows multi-turn conversations.`,
+Maintains conversation context and allows multi-turn conversations.
+Type "exit" or press Ctrl-D to end the session, or /help for in-session commands.
+Sessions are saved under ~/.config/zik/sessions when chat.save_history is enabled.`,
+		Example: `  zik chat                      # Start a new session
+  zik chat --continue           # Continue the most recent session
+  zik chat --resume 20260101    # Resume a session by ID or ID prefix`,
+		Args: cobra.NoArgs,
 		RunE: runChat,
 	}
 )
 
+func init() {
+	chatCmd.Flags().StringVarP(&chatResume, "resume", "r", "", "Resume a saved session by ID")
+	chatCmd.Flags().BoolVarP(&chatContinue, "continue", "c", false, "Continue the most recent session")
+	chatCmd.MarkFlagsMutuallyExclusive("resume", "continue")
+}
+
+// chatSession holds the state of an interactive chat
+type chatSession struct {
+	cfg         *config.Config
+	client      *ai.Client
+	store       *session.Store
+	sess        *session.Session
+	context     *history.Manager
+	commands    *slash.Registry
+	attachments []string
+}
+
 func runChat(cmd *cobra.Command, args []string) error {
-	// TODO: Implement interactive chat with bubbletea
+	// Load configuration
+	cfg, origins, err := loadConfigWithOrigins(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.ValidateContextBudget(origins); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	store, err := openSessionStore(cfg)
+	if err != nil {
+		return err
+	}
+
+	sess, err := startSession(store, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Resumed sessions keep talking to the model they were started with
+	if sess.Model != "" {
+		cfg.Model = sess.Model
+	}
+
+	chat := &chatSession{
+		cfg:    cfg,
+		client: newAIClient(cfg),
+		store:  store,
+		sess:   sess,
+	}
+	chat.commands = chat.newCommandRegistry()
+
+	// Keep the conversation and the reserved reply tokens within the context budget
+	var summarizer history.Summarizer
+	if cfg.Chat.Summarize {
+		summarizer = chat.summarize
+	}
+	chat.context = history.NewManager(cfg.Chat.ContextTokens, cfg.MaxTokens, summarizer)
+
+	if sess.Exchanges() > 0 {
+		fmt.Printf("Resumed session %s (%d exchanges): %s\n", sess.ID, sess.Exchanges(), sess.DeriveTitle())
+	}
+	fmt.Println("Chat session started. Type \"exit\" or press Ctrl-D to quit, /help for commands.")
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("\n> ")
+		line, err := reader.ReadString('\n')
+		if err == io.EOF && line == "" {
+			fmt.Println()
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		input := strings.TrimSpace(line)
+		if input == "" {
+			continue
+		}
+		if input == "exit" || input == "quit" {
+			return nil
+		}
+
+		// Slash commands are handled locally and never sent to the model
+		handled, err := chat.commands.Dispatch(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		if handled {
+			continue
+		}
+
+		// Interrupted requests are already marked in the output
+		if err := chat.send(input); err != nil && !errors.Is(err, errInterrupted) {
+			printError(err)
+		}
+	}
+}
+
+// send adds a user message with any pending attachments and asks for a reply
+func (c *chatSession) send(input string) error {
+	content := input
+	if len(c.attachments) > 0 {
+		content = strings.Join(c.attachments, "\n\n") + "\n\n" + input
+	}
+
+	c.sess.Messages = append(c.sess.Messages, ai.Message{Role: "user", Content: content})
+	if err := c.respond(); err != nil {
+		// Drop the unanswered question so the user can simply ask again
+		c.sess.Messages = c.sess.Messages[:len(c.sess.Messages)-1]
+		return err
+	}
+
+	c.attachments = nil
 	return nil
 }
+
+// respond asks the AI to answer the last user message and records the reply
+func (c *chatSession) respond() error {
+	c.sess.TrimMessages(c.cfg.Chat.HistoryLimit)
+	window := c.fitContext()
+
+	fmt.Println()
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	reply, err := printReply(ctx, c.client, window, c.cfg)
+	switch {
+	case errors.Is(err, errInterrupted):
+		printInterrupted()
+		if reply == "" {
+			return err
+		}
+	case errors.Is(err, context.DeadlineExceeded) && reply != "":
+		fmt.Fprintln(os.Stderr, render.Dim(fmt.Sprintf("[timed out after %s]", c.cfg.Chat.Timeout)))
+	case err != nil:
+		return err
+	}
+	if err != nil {
+		// Keep the partial reply, marked so that the model does not take it as complete
+		reply += "\n\n" + truncatedMarker
+	}
+
+	c.sess.Messages = append(c.sess.Messages, ai.Message{Role: "assistant", Content: reply})
+
+	if err := c.autosave(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	return nil
+}
+
+// fitContext returns the messages to send, dropping or summarizing old turns that no longer fit the context budget
+// The session keeps every message; only the summary and the first message still sent are recorded on it
+func (c *chatSession) fitContext() []ai.Message {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	result := c.context.Fit(ctx, history.Window(c.sess.Messages, c.sess.Summary, c.sess.ContextStart))
+	c.sess.Summary = result.Summary()
+	c.sess.ContextStart = len(c.sess.Messages) - result.Kept()
+
+	switch {
+	case result.Summarized:
+		fmt.Printf("(Condensed %d earlier messages into a summary to fit the context window)\n", result.Dropped)
+	case result.SummaryError != nil:
+		fmt.Fprintf(os.Stderr, "Warning: failed to summarize history, dropped %d earlier messages: %v\n", result.Dropped, result.SummaryError)
+	case result.Dropped > 0:
+		fmt.Printf("(Dropped %d earlier messages to fit the context window)\n", result.Dropped)
+	}
+
+	return result.Messages
+}
+
+// summarize asks the AI to condense turns dropped from the context window
+func (c *chatSession) summarize(ctx context.Context, messages []ai.Message) (string, error) {
+	request := []ai.Message{
+		{Role: "system", Content: prompt.ChatSummarySystemPrompt()},
+		{Role: "user", Content: prompt.ChatSummaryUserPrompt(history.Transcript(messages))},
+	}
+
+	resp, err := c.client.Chat(ctx, request, 0.3, 1000)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// requestContext returns a context bounded by the chat timeout and cancelled by Ctrl-C
+// Ctrl-C is only caught during requests, so at the prompt it still ends the session
+func (c *chatSession) requestContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := interruptContext(context.Background())
+	if c.cfg.Chat.Timeout <= 0 {
+		return ctx, cancel
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, c.cfg.Chat.Timeout)
+	return timeoutCtx, func() {
+		timeoutCancel()
+		cancel()
+	}
+}
+
+// lastMessage returns the index of the most recent message with the given role
+func (c *chatSession) lastMessage(role string) int {
+	for i := len(c.sess.Messages) - 1; i >= 0; i-- {
+		if c.sess.Messages[i].Role == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// startSession resumes the session requested by flags or creates a new one
+func startSession(store *session.Store, cfg *config.Config) (*session.Session, error) {
+	var (
+		sess *session.Session
+		err  error
+	)
+
+	switch {
+	case chatResume != "":
+		sess, err = store.Load(chatResume)
+	case chatContinue:
+		sess, err = store.Latest()
+		if errors.Is(err, session.ErrNotFound) {
+			return nil, fmt.Errorf("no saved sessions to continue")
+		}
+	default:
+		sess = session.New(cfg.Model)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// Conversation history always starts with the system prompt
+	if len(sess.Messages) == 0 || sess.Messages[0].Role != "system" {
+		system := ai.Message{Role: "system", Content: prompt.WithInstructions(prompt.ChatSystemPrompt(), cfg.Instructions)}
+		sess.Messages = append([]ai.Message{system}, sess.Messages...)
+	}
+
+	return sess, nil
+}
+
+// openSessionStore opens the on-disk session store bounded by the chat history limit
+func openSessionStore(cfg *config.Config) (*session.Store, error) {
+	dir, err := session.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate sessions directory: %w", err)
+	}
+	return session.NewStore(dir, cfg.Chat.HistoryLimit), nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/slash"
+)
+
+// newCommandRegistry registers the slash commands available inside a chat session
+func (c *chatSession) newCommandRegistry() *slash.Registry {
+	registry := slash.NewRegistry()
+
+	registry.Register(slash.Command{
+		Name:        "model",
+		Usage:       "/model [name]",
+		Description: "Show or switch the model",
+		Run:         c.cmdModel,
+	})
+	registry.Register(slash.Command{
+		Name:        "system",
+		Usage:       "/system [prompt]",
+		Description: "Show or replace the system prompt (\"default\" restores it)",
+		Run:         c.cmdSystem,
+	})
+	registry.Register(slash.Command{
+		Name:        "temperature",
+		Usage:       "/temperature [value]",
+		Description: "Show or set the sampling temperature (0-2)",
+		Run:         c.cmdTemperature,
+	})
+	registry.Register(slash.Command{
+		Name:        "file",
+		Usage:       "/file <path>",
+		Description: "Attach a file to the next message",
+		Run:         c.cmdFile,
+	})
+	registry.Register(slash.Command{
+		Name:        "clear",
+		Description: "Clear the conversation history",
+		Run:         c.cmdClear,
+	})
+	registry.Register(slash.Command{
+		Name:        "retry",
+		Description: "Regenerate the last reply",
+		Run:         c.cmdRetry,
+	})
+	registry.Register(slash.Command{
+		Name:        "undo",
+		Description: "Remove the last question and its reply",
+		Run:         c.cmdUndo,
+	})
+	registry.Register(slash.Command{
+		Name:        "save",
+		Usage:       "/save [title]",
+		Description: "Save the session now, optionally renaming it",
+		Run:         c.cmdSave,
+	})
+	registry.Register(slash.Command{
+		Name:        "copy",
+		Description: "Copy the last reply to the clipboard",
+		Run:         c.cmdCopy,
+	})
+	registry.Register(slash.Command{
+		Name:        "help",
+		Description: "Show available commands",
+		Run: func(string) error {
+			fmt.Println("Available commands:")
+			fmt.Print(registry.Help())
+			return nil
+		},
+	})
+
+	return registry
+}
+
+func (c *chatSession) cmdModel(args string) error {
+	if args == "" {
+		fmt.Printf("Current model: %s\n", c.cfg.Model)
+		if provider, err := c.cfg.ActiveProvider(); err == nil && len(provider.Models) > 0 {
+			fmt.Printf("Available: %s\n", strings.Join(provider.Models, ", "))
+		}
+		return nil
+	}
+
+	c.cfg.Model = args
+	c.sess.Model = args
+	c.client = newAIClient(c.cfg)
+	fmt.Printf("Switched model to %s\n", args)
+	return nil
+}
+
+func (c *chatSession) cmdSystem(args string) error {
+	if args == "" {
+		fmt.Println(c.sess.Messages[0].Content)
+		return nil
+	}
+
+	if args == "default" {
+		args = prompt.WithInstructions(prompt.ChatSystemPrompt(), c.cfg.Instructions)
+	}
+	c.sess.Messages[0] = ai.Message{Role: "system", Content: args}
+	fmt.Println("System prompt updated.")
+	return nil
+}
+
+func (c *chatSession) cmdTemperature(args string) error {
+	if args == "" {
+		fmt.Printf("Current temperature: %g\n", c.cfg.Temperature)
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(args, 64)
+	if err != nil || value < 0 || value > 2 {
+		return fmt.Errorf("temperature must be a number between 0 and 2")
+	}
+
+	c.cfg.Temperature = value
+	fmt.Printf("Temperature set to %g\n", value)
+	return nil
+}
+
+func (c *chatSession) cmdFile(args string) error {
+	if args == "" {
+		return fmt.Errorf("usage: /file <path>")
+	}
+
+	data, err := os.ReadFile(args)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	c.attachments = append(c.attachments, prompt.FileBlock(args, string(data)))
+	fmt.Printf("Attached %s (%d bytes) to the next message.\n", args, len(data))
+	return nil
+}
+
+func (c *chatSession) cmdClear(string) error {
+	c.sess.Messages = c.sess.Messages[:1]
+	c.sess.Summary, c.sess.ContextStart = "", 0
+	c.attachments = nil
+	fmt.Println("Conversation cleared.")
+	return nil
+}
+
+func (c *chatSession) cmdRetry(string) error {
+	idx := c.lastMessage("user")
+	if idx < 0 {
+		return fmt.Errorf("nothing to retry")
+	}
+
+	// Drop the previous answer and ask again with the same question
+	removed := c.sess.Messages[idx+1:]
+	c.sess.Messages = c.sess.Messages[:idx+1]
+	if err := c.respond(); err != nil {
+		c.sess.Messages = append(c.sess.Messages, removed...)
+		return err
+	}
+	return nil
+}
+
+func (c *chatSession) cmdUndo(string) error {
+	idx := c.lastMessage("user")
+	if idx < 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	c.sess.Messages = c.sess.Messages[:idx]
+	fmt.Println("Removed the last exchange.")
+	return c.autosave()
+}
+
+func (c *chatSession) cmdSave(args string) error {
+	if args != "" {
+		c.sess.Title = args
+	}
+
+	if err := c.store.Save(c.sess); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	fmt.Printf("Session saved as %s\n", c.sess.ID)
+	return nil
+}
+
+func (c *chatSession) cmdCopy(string) error {
+	idx := c.lastMessage("assistant")
+	if idx < 0 {
+		return fmt.Errorf("no reply to copy yet")
+	}
+
+	if err := copyToClipboard(c.sess.Messages[idx].Content); err != nil {
+		return err
+	}
+	fmt.Println("Copied the last reply to the clipboard.")
+	return nil
+}
+
+// autosave persists the session when history saving is enabled
+func (c *chatSession) autosave() error {
+	if !c.cfg.Chat.SaveHistory || c.sess.Exchanges() == 0 {
+		return nil
+	}
+	if err := c.store.Save(c.sess); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
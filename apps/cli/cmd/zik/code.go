@@ -1,10 +1,32 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/diff"
+	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/review"
+	"github.com/zarazaex69/zik/apps/cli/internal/source"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
 )
 
 var (
+	reviewStaged bool
+	reviewRange  string
+	reviewFailOn string
+	reviewJSON   bool
+
+	explainSymbol string
+	explainDepth  string
+
 	codeCmd = &cobra.Command{
 		Use:   "code",
 		Short: "Code analysis and assistance commands",
@@ -14,28 +36,280 @@ var (
 	codeReviewCmd = &cobra.Command{
 		Use:   "review",
 		Short: "Review code changes",
-		RunE:  runCodeReview,
+		Long: `Review a git diff with AI and report findings grouped per file.
+By default all uncommitted changes (staged and unstaged) are reviewed.
+With --fail-on the command exits non-zero when a finding reaches that severity,
+which makes it usable as a local pre-push gate.`,
+		Example: `  zik code review                       # Review uncommitted changes
+  zik code review --staged              # Review staged changes only
+  zik code review --range main..HEAD    # Review a commit range
+  zik code review --fail-on high        # Exit non-zero on high or critical findings`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runCodeReview,
 	}
 
 	codeExplainCmd = &cobra.Command{
-		Use:   "explain <file>",
+		Use:   "explain <file>[:start-end]",
 		Short: "Explain code in a file",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runCodeExplain,
+		Long: `Explain a file, a line range or a single Go symbol.
+Large inputs are split into chunks that fit the context window and explained part by part.`,
+		Example: `  zik code explain main.go                     # Summarize a whole file
+  zik code explain main.go:40-80               # Explain a line range
+  zik code explain client.go --symbol Chat     # Explain one Go function or type
+  zik code explain client.go --symbol Client.Chat --depth line`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCodeExplain,
 	}
 )
 
+// severityColors maps review severities to terminal colors
+var severityColors = map[review.Severity]string{
+	review.SeverityInfo:     "39",
+	review.SeverityLow:      "42",
+	review.SeverityMedium:   "226",
+	review.SeverityHigh:     "208",
+	review.SeverityCritical: "196",
+}
+
 func init() {
+	codeReviewCmd.Flags().BoolVarP(&reviewStaged, "staged", "s", false, "Review staged changes only")
+	codeReviewCmd.Flags().StringVarP(&reviewRange, "range", "r", "", "Review a revision range (e.g. main..HEAD)")
+	codeReviewCmd.Flags().StringVar(&reviewFailOn, "fail-on", "", "Exit non-zero on findings at or above this severity (info, low, medium, high, critical)")
+	codeReviewCmd.Flags().BoolVar(&reviewJSON, "json", false, "Print findings as JSON")
+	codeReviewCmd.MarkFlagsMutuallyExclusive("staged", "range")
+
+	codeExplainCmd.Flags().StringVar(&explainSymbol, "symbol", "", "Explain a Go function, method (Type.Method) or type")
+	codeExplainCmd.Flags().StringVarP(&explainDepth, "depth", "d", "summary", "Level of detail (summary, line)")
+
 	codeCmd.AddCommand(codeReviewCmd)
 	codeCmd.AddCommand(codeExplainCmd)
 }
 
 func runCodeReview(cmd *cobra.Command, args []string) error {
-	// TODO: Implement code review
+	// Validate the threshold before spending a request on the review
+	var threshold review.Severity
+	if reviewFailOn != "" {
+		var err error
+		threshold, err = review.ParseSeverity(reviewFailOn)
+		if err != nil {
+			return fmt.Errorf("invalid --fail-on: %w", err)
+		}
+	}
+
+	// Load configuration
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitClient := git.NewClient()
+	if !gitClient.IsRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	// Get diff based on flags
+	var diff string
+	switch {
+	case reviewRange != "":
+		diff, err = gitClient.GetDiffRange(reviewRange)
+	case reviewStaged:
+		diff, err = gitClient.GetDiffStaged()
+	default:
+		diff, err = gitClient.GetDiffAll()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get git diff: %w", err)
+	}
+
+	if diff == "" {
+		return fmt.Errorf("no changes to review")
+	}
+
+	if !reviewJSON {
+		fmt.Fprintln(os.Stderr, "Reviewing changes...")
+	}
+
+	system := prompt.WithInstructions(prompt.ReviewSystemPrompt(), cfg.Instructions)
+	content, shortened, total := reviewDiff(diff, max(inputBudget(cfg)-tokens.Estimate(system), minShareTokens))
+	if shortened > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: the diff does not fit the context window; %d of %d files were cut short and their ends are not reviewed\n", shortened, total)
+	}
+
+	aiClient := newAIClient(cfg)
+	ctx, cancel := interruptContext(cmd.Context())
+	defer cancel()
+
+	messages := []ai.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt.ReviewUserPrompt(content)},
+	}
+
+	resp, err := aiClient.Chat(ctx, messages, 0.2, cfg.MaxTokens) // Low temperature for consistent findings
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no response from AI")
+	}
+
+	findings, err := review.Parse(resp.Choices[0].Message.Content)
+	if err != nil {
+		return err
+	}
+
+	reasoning := resp.Choices[0].Message.Reasoning
+	if reviewJSON {
+		if err := writeReviewJSON(os.Stdout, reasoning, findings); err != nil {
+			return err
+		}
+	} else {
+		view := &reasoningView{show: cfg.ShowReasoning}
+		view.write(reasoning)
+		view.close()
+		printFindings(findings)
+	}
+
+	if reviewFailOn != "" {
+		if count := review.CountAtLeast(findings, threshold); count > 0 {
+			return fmt.Errorf("review failed: %d finding(s) at or above %s severity", count, threshold)
+		}
+	}
+
 	return nil
 }
 
+// writeReviewJSON prints the review as one object, so its shape does not depend on whether reasoning was asked for
+func writeReviewJSON(w io.Writer, reasoning string, findings []review.Finding) error {
+	if findings == nil {
+		findings = []review.Finding{}
+	}
+
+	output := struct {
+		Reasoning string           `json:"reasoning"`
+		Findings  []review.Finding `json:"findings"`
+	}{reasoning, findings}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("failed to encode findings: %w", err)
+	}
+	return nil
+}
+
+// reviewDiff compacts a diff for review, truncating each file to its share of budget when it does not fit
+// It returns the diff with the number of files that were truncated and the number of files in total
+func reviewDiff(raw string, budget int) (string, int, int) {
+	files := diff.Parse(raw)
+	compact := diff.Render(files)
+	if len(files) == 0 || tokens.Estimate(compact) <= budget {
+		return compact, 0, len(files)
+	}
+
+	perFile := shareBudget(budget, len(files))
+	parts := make([]string, 0, len(files))
+	shortened := 0
+	for _, f := range files {
+		text := f.Compact()
+		truncated := diff.Truncate(text, perFile)
+		if truncated != text {
+			shortened++
+		}
+		parts = append(parts, truncated)
+	}
+	return strings.Join(parts, "\n"), shortened, len(files)
+}
+
+// printFindings prints review findings grouped per file
+func printFindings(findings []review.Finding) {
+	if len(findings) == 0 {
+		fmt.Println("\nNo issues found.")
+		return
+	}
+
+	groups := review.GroupByFile(findings)
+	for _, group := range groups {
+		fmt.Println()
+		fmt.Println(render.Bold(group.File))
+
+		for _, finding := range group.Findings {
+			location := ""
+			if finding.Line > 0 {
+				location = fmt.Sprintf("L%d", finding.Line)
+			}
+
+			severity := render.Color(fmt.Sprintf("%-8s", finding.Severity), severityColors[finding.Severity])
+			fmt.Printf("  %s %s %s %s\n", render.Dim(fmt.Sprintf("%-6s", location)), severity, render.Dim("["+finding.Category+"]"), finding.Message)
+			if finding.Suggestion != "" {
+				fmt.Printf("    %s %s\n", render.Dim("→"), finding.Suggestion)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d finding(s) in %d file(s)\n", len(findings), len(groups))
+}
+
 func runCodeExplain(cmd *cobra.Command, args []string) error {
-	// TODO: Implement code explanation
+	if explainDepth != "summary" && explainDepth != "line" {
+		return fmt.Errorf("invalid --depth %q (use summary or line)", explainDepth)
+	}
+
+	target, err := source.ParseTarget(args[0])
+	if err != nil {
+		return err
+	}
+
+	if explainSymbol != "" {
+		if target.Start != 0 {
+			return fmt.Errorf("--symbol cannot be combined with a line range")
+		}
+		data, err := os.ReadFile(target.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", target.Path, err)
+		}
+		target.Start, target.End, err = source.FindSymbol(target.Path, data, explainSymbol)
+		if err != nil {
+			return err
+		}
+	}
+
+	lines, start, err := target.Lines()
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("%s is empty", target.Path)
+	}
+
+	// Load configuration
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	aiClient := newAIClient(cfg)
+	ctx, cancel := interruptContext(cmd.Context())
+	defer cancel()
+	language := prompt.Language(target.Path)
+	chunks := source.Split(lines, start, inputBudget(cfg))
+
+	for i, chunk := range chunks {
+		if len(chunks) > 1 {
+			header := fmt.Sprintf("── %s lines %d-%d (part %d/%d)", target.Path, chunk.Start, chunk.End, i+1, len(chunks))
+			fmt.Println(render.Dim(header))
+		}
+
+		messages := []ai.Message{
+			{Role: "system", Content: prompt.WithInstructions(prompt.ExplainSystemPrompt(explainDepth), cfg.Instructions)},
+			{Role: "user", Content: prompt.ExplainUserPrompt(target.Path, language, chunk.Start, chunk.End, i+1, len(chunks), chunk.Numbered())},
+		}
+
+		if _, err := printReply(ctx, aiClient, messages, cfg); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/review"
+)
+
+func TestReviewDiff(t *testing.T) {
+	var raw strings.Builder
+	for _, name := range []string{"a.go", "b.go"} {
+		fmt.Fprintf(&raw, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,0 +1,400 @@\n", name, name, name, name)
+		for i := range 400 {
+			fmt.Fprintf(&raw, "+line %d of %s\n", i, name)
+		}
+	}
+
+	// A diff within the budget is sent whole
+	content, shortened, total := reviewDiff(raw.String(), 1_000_000)
+	if shortened != 0 || total != 2 || strings.Contains(content, "omitted") {
+		t.Errorf("reviewDiff() shortened %d of %d files, want none of 2", shortened, total)
+	}
+
+	// Otherwise every file keeps its header and is cut to its share
+	content, shortened, total = reviewDiff(raw.String(), 500)
+	if shortened != 2 || total != 2 {
+		t.Errorf("reviewDiff() shortened %d of %d files, want 2 of 2", shortened, total)
+	}
+	for _, want := range []string{"diff --git a/a.go", "diff --git a/b.go", "more lines omitted"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("reviewDiff() = %q, want it to contain %q", content, want)
+		}
+	}
+}
+
+func TestWriteReviewJSON(t *testing.T) {
+	// Without reasoning the output keeps the same shape, with an empty reasoning and findings array
+	var buf bytes.Buffer
+	if err := writeReviewJSON(&buf, "", nil); err != nil {
+		t.Fatalf("writeReviewJSON() error = %v", err)
+	}
+	var empty map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &empty); err != nil {
+		t.Fatalf("writeReviewJSON() = %q, want a JSON object: %v", buf.String(), err)
+	}
+	if string(empty["reasoning"]) != `""` || string(empty["findings"]) != "[]" {
+		t.Errorf("writeReviewJSON() = %q, want empty reasoning and findings", buf.String())
+	}
+
+	buf.Reset()
+	findings := []review.Finding{{File: "a.go", Line: 3, Severity: review.SeverityHigh, Message: "m"}}
+	if err := writeReviewJSON(&buf, "thought", findings); err != nil {
+		t.Fatalf("writeReviewJSON() error = %v", err)
+	}
+	var report struct {
+		Reasoning string           `json:"reasoning"`
+		Findings  []review.Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("writeReviewJSON() = %q: %v", buf.String(), err)
+	}
+	if report.Reasoning != "thought" || len(report.Findings) != 1 || report.Findings[0] != findings[0] {
+		t.Errorf("writeReviewJSON() = %+v, want the reasoning and findings", report)
+	}
+}
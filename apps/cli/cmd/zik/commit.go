@@ -1,46 +1,105 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/commitlint"
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
 	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/editor"
 	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/hook"
 	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/scope"
 )
 
 var (
-	commitStaged bool
-	commitAll    bool
-	commitApply  bool
-	commitType   string
+	commitStaged     bool
+	commitAll        bool
+	commitApply      bool
+	commitType       string
+	commitDryRun     bool
+	commitHook       string
+	commitCandidates int
+	commitSplit      bool
 
 	commitCmd = &cobra.Command{
 		Use:   "commit",
 		Short: "Generate conventional commit message from git diff",
 		Long: `Analyze staged changes and generate a conventional commit message.
-Uses AI to understand the changes and create a meaningful commit message following the Conventional Commits standard.`,
+Uses AI to understand the changes and create a meaningful commit message following the Conventional Commits standard.
+
+Lockfiles, generated code and binary files are reduced to a one-line note. When the remaining diff
+exceeds commit.max_diff_tokens, each file is summarized first and the message is written from the summaries.
+
+With --split, all changes since HEAD are grouped by file and hunk into several commits. The plan can be
+reviewed, reordered and edited before the groups are staged and committed one by one; if any step fails,
+HEAD and the index are restored.`,
 		Example: `  zik commit                    # Generate message for staged changes
   zik commit --all              # Generate message for all changes
   zik commit --apply            # Generate and apply commit
-  zik commit --type feat        # Prefer 'feat' type`,
-		RunE: runCommit,
+  zik commit --type feat        # Prefer 'feat' type
+  zik commit --dry-run          # Print the prompt without calling the AI
+  zik commit --candidates 3     # Pick from three alternatives
+  zik commit --split            # Split all changes into several commits`,
+		Args:         cobra.MaximumNArgs(2),
+		SilenceUsage: true,
+		RunE:         runCommit,
 	}
 )
 
+const (
+	// commitHookTimeout bounds message generation inside a git hook so commits never hang
+	commitHookTimeout = 60 * time.Second
+
+	// commitRepairAttempts is how many times an invalid message is sent back to be fixed
+	commitRepairAttempts = 2
+
+	// commitStyleSample is the number of recent commits the house style is learned from
+	commitStyleSample = 100
+)
+
 func init() {
 	commitCmd.Flags().BoolVarP(&commitStaged, "staged", "s", true, "Analyze staged changes only")
 	commitCmd.Flags().BoolVarP(&commitAll, "all", "a", false, "Analyze all changes (staged + unstaged)")
 	commitCmd.Flags().BoolVarP(&commitApply, "apply", "y", false, "Automatically apply the generated commit message")
 	commitCmd.Flags().StringVarP(&commitType, "type", "t", "", "Preferred commit type (feat, fix, docs, etc.)")
+	commitCmd.Flags().BoolVar(&commitDryRun, "dry-run", false, "Print the prompt and its estimated size without calling the AI")
+	commitCmd.Flags().StringVar(&commitHook, "hook", "", "Run as a prepare-commit-msg hook: write the message into this file (used by zik hook install)")
+	commitCmd.Flags().IntVarP(&commitCandidates, "candidates", "n", 1, "Generate several alternative messages to pick from")
+	commitCmd.Flags().BoolVar(&commitSplit, "split", false, "Split all changes since HEAD into several commits")
+	commitCmd.MarkFlagsMutuallyExclusive("candidates", "apply")
+	commitCmd.MarkFlagsMutuallyExclusive("split", "candidates")
+	commitCmd.MarkFlagsMutuallyExclusive("split", "dry-run")
+	commitCmd.MarkFlagsMutuallyExclusive("split", "hook")
+	commitCmd.MarkFlagsMutuallyExclusive("hook", "apply")
+	commitCmd.MarkFlagsMutuallyExclusive("hook", "dry-run")
+	bindConfigFlag(commitCmd.Flags(), "type", "commit.preferred_type")
 }
 
 func runCommit(cmd *cobra.Command, args []string) error {
+	if commitHook != "" {
+		return runCommitHook(cmd, args)
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(args, " "))
+	}
+	if commitCandidates < 1 {
+		return fmt.Errorf("--candidates must be at least 1")
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, origins, err := loadConfigWithOrigins(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -53,6 +112,10 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not a git repository")
 	}
 
+	if commitSplit {
+		return runCommitSplit(cmd.Context(), cfg, origins, gitClient)
+	}
+
 	// Get diff based on flags
 	var diff string
 	if commitAll {
@@ -68,34 +131,49 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no changes to commit")
 	}
 
+	generator := newCommitGenerator(cfg, origins, gitClient, diff)
+
+	if commitDryRun {
+		generator.changes.printDryRun(generator.system)
+		return nil
+	}
+
 	// Generate commit message using AI
 	fmt.Println("Analyzing changes...")
 
-	aiClient := ai.NewClient(cfg)
-	ctx := context.Background()
+	reader := bufio.NewReader(os.Stdin)
 
-	// Build prompt for commit message generation
-	systemPrompt := prompt.CommitSystemPrompt(cfg.Commit.ConventionalCommits, commitType)
-	userPrompt := prompt.CommitUserPrompt(diff)
+	var commitMessage string
+	regenerate := true
 
 	for {
-		messages := []ai.Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
-		}
+		if regenerate {
+			regenerate = false
 
-		// Get AI response
-		resp, err := aiClient.Chat(ctx, messages, 0.3, 200) // Low temperature for consistency
-		if err != nil {
-			return fmt.Errorf("AI request failed: %w", err)
-		}
+			// Ctrl-C is only caught while waiting for the AI, so it still exits at the prompts
+			ctx, cancel := interruptContext(cmd.Context())
+			candidates, err := generator.candidates(ctx, commitCandidates)
+			cancel()
+			if err != nil {
+				return err
+			}
 
-		if len(resp.Choices) == 0 {
-			return fmt.Errorf("no response from AI")
+			commitMessage = candidates[0]
+			if len(candidates) > 1 {
+				var action string
+				commitMessage, action = chooseCandidate(reader, candidates)
+				switch action {
+				case "cancel":
+					fmt.Println("Commit cancelled.")
+					return nil
+				case "regenerate":
+					fmt.Println("\nRegenerating...")
+					regenerate = true
+					continue
+				}
+			}
 		}
 
-		commitMessage := resp.Choices[0].Message.Content
-
 		// Display generated commit message
 		fmt.Println("\nGenerated commit message:")
 		fmt.Println("─────────────────────────────")
@@ -112,11 +190,15 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		}
 
 		// Interactive prompt
-		fmt.Print("\nAccept? [Y]es / [N]o / [R]egenerate: ")
-		var response string
-		fmt.Scanln(&response)
+		fmt.Print("\nAccept? [Y]es / [N]o / [R]egenerate / [E]dit / [F]eedback: ")
+		response, err := readLine(reader)
+		if err != nil {
+			// Without input there is no one to confirm the commit
+			fmt.Println("\nCommit cancelled.")
+			return nil
+		}
 
-		switch strings.ToLower(strings.TrimSpace(response)) {
+		switch strings.ToLower(response) {
 		case "y", "yes", "":
 			if err := gitClient.Commit(commitMessage); err != nil {
 				return fmt.Errorf("failed to commit: %w", err)
@@ -128,10 +210,355 @@ func runCommit(cmd *cobra.Command, args []string) error {
 			return nil
 		case "r", "regenerate":
 			fmt.Println("\nRegenerating...")
-			continue
+			regenerate = true
+		case "e", "edit":
+			edited, err := editCommitMessage(commitMessage)
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			case edited == "":
+				fmt.Println("Edited message is empty, keeping the previous one.")
+			default:
+				commitMessage = edited
+			}
+		case "f", "feedback":
+			fmt.Print("Feedback: ")
+			instruction, err := readLine(reader)
+			if err != nil {
+				fmt.Println("\nCommit cancelled.")
+				return nil
+			}
+			if instruction == "" {
+				continue
+			}
+			generator.refine(commitMessage, instruction)
+			fmt.Println("\nRegenerating with feedback...")
+			regenerate = true
 		default:
-			fmt.Println("Invalid option. Please choose Y, N, or R.")
+			fmt.Println("Invalid option. Please choose Y, N, R, E or F.")
+		}
+	}
+}
+
+// chooseCandidate lists alternative messages and asks the user to pick one
+// The returned action is "pick", "regenerate" or "cancel"; the end of input cancels
+func chooseCandidate(reader *bufio.Reader, candidates []string) (string, string) {
+	for i, candidate := range candidates {
+		fmt.Printf("\n%s\n%s\n", render.Bold(fmt.Sprintf("[%d]", i+1)), candidate)
+	}
+
+	for {
+		fmt.Printf("\nPick a message [1-%d], [R]egenerate or [N]o: ", len(candidates))
+		response, err := readLine(reader)
+		if err != nil {
+			return "", "cancel"
+		}
+
+		switch response = strings.ToLower(response); response {
+		case "r", "regenerate":
+			return "", "regenerate"
+		case "n", "no":
+			return "", "cancel"
+		}
+
+		if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(candidates) {
+			return candidates[n-1], "pick"
+		}
+		fmt.Printf("Invalid option. Please choose a number between 1 and %d, R or N.\n", len(candidates))
+	}
+}
+
+// editCommitMessage opens message in the user's editor and returns the result without comment lines
+func editCommitMessage(message string) (string, error) {
+	edited, err := editor.Edit(message+"\n\n# Edit the commit message. Lines starting with '#' are ignored.\n", "zik-commit-*.txt")
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(edited, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// readLine reads one line of user input
+// A last line without a newline is still returned; after it the error is io.EOF
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// runCommitHook pre-fills the message file of a prepare-commit-msg hook without any interaction
+func runCommitHook(cmd *cobra.Command, args []string) error {
+	source := ""
+	if len(args) > 0 {
+		source = args[0]
+	}
+	if !hook.ShouldGenerate(source) {
+		return nil
+	}
+
+	cfg, origins, err := loadConfigWithOrigins(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// git sets GIT_INDEX_FILE for the hook, so this also covers "git commit -a"
+	gitClient := git.NewClient()
+	diff, err := gitClient.GetDiffStaged()
+	if err != nil {
+		return fmt.Errorf("failed to get git diff: %w", err)
+	}
+	if diff == "" {
+		return nil
+	}
+
+	existing, err := os.ReadFile(commitHook)
+	if err != nil {
+		return fmt.Errorf("failed to read commit message file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), commitHookTimeout)
+	defer cancel()
+
+	message, err := newCommitGenerator(cfg, origins, gitClient, diff).generate(ctx, 0.3)
+	if err != nil {
+		return err
+	}
+
+	// Keep git's comment lines below the generated message
+	content := message + "\n" + string(existing)
+	if err := os.WriteFile(commitHook, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write commit message file: %w", err)
+	}
+	return nil
+}
+
+// commitGenerator writes commit messages for a prepared diff
+type commitGenerator struct {
+	cfg          *config.Config
+	client       *ai.Client
+	changes      *commitDiff
+	options      prompt.CommitOptions
+	system       string
+	rules        commitmsg.Rules // Validation rules, adjusted to the learned style
+	conventional bool            // Validate messages against Conventional Commits
+	issue        commitmsg.Footer
+	hasIssue     bool
+	feedback     []ai.Message // Earlier candidates and the user's feedback on them
+}
+
+// newCommitGenerator builds the prompts for diff, learning the house style from recent commits
+// and inferring an issue reference from the branch
+func newCommitGenerator(cfg *config.Config, origins config.Origins, gitClient *git.Client, diff string) *commitGenerator {
+	g := &commitGenerator{
+		cfg:     cfg,
+		client:  newAIClient(cfg),
+		changes: newCommitDiff(diff, commitDiffBudget(cfg)),
+		options: prompt.CommitOptions{
+			PreferredType: cfg.Commit.PreferredType,
+			Body:          cfg.Commit.IncludeBody,
+		},
+		conventional: cfg.Commit.ConventionalCommits,
+	}
+
+	// Recent history shows house conventions such as scopes, capitalisation and ticket references
+	if cfg.Commit.LearnStyle {
+		if history, err := gitClient.GetLog(commitStyleSample); err == nil {
+			style := commitmsg.LearnStyle(history)
+			g.conventional = conventionalStyle(cfg.Commit.ConventionalCommits, origins["commit.conventional_commits"], style)
+
+			// The learned conventions must not contradict the setting that won
+			style.Conventional = g.conventional
+			g.options.Conventions, g.options.Examples = style.Conventions(), style.Examples
+			g.rules.Capitalized = style.Capitalized
+		}
+	}
+	g.options.Conventional = g.conventional
+	// Scopes follow the changed paths, and commitlint rules are honoured so messages pass CI
+	if root, err := gitClient.TopLevel(); err == nil {
+		g.applyCommitlint(root)
+		g.options.Areas = describeAreas(scope.Infer(root, g.changes.paths(), cfg.Commit.Scopes), g.options.Scopes)
+	}
+
+	g.system = prompt.WithInstructions(prompt.CommitSystemPrompt(g.options), cfg.Instructions)
+
+	// Branches such as feat/PROJ-42-login or fix/123-crash reference an issue
+	if cfg.Commit.LinkIssues {
+		if branch, err := gitClient.GetBranch(); err == nil {
+			g.issue, g.hasIssue = commitmsg.IssueFooter(branch)
+		}
+	}
+
+	return g
+}
+
+// conventionalStyle decides whether messages follow Conventional Commits
+// The learned history only wins over the built-in default, never over a configured value
+func conventionalStyle(configured bool, origin config.Origin, style commitmsg.Style) bool {
+	if origin.Layer == config.LayerDefault && style.Sampled > 0 {
+		return style.Conventional
+	}
+	return configured
+}
+
+// applyCommitlint restricts types, scopes and the subject length to the commitlint config in root
+func (g *commitGenerator) applyCommitlint(root string) {
+	lint, found, err := commitlint.Find(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring commitlint config: %v\n", err)
+		return
+	}
+	if !found {
+		return
+	}
+
+	g.options.Types, g.rules.Types = lint.Types, lint.Types
+	g.options.Scopes, g.rules.Scopes = lint.Scopes, lint.Scopes
+	if lint.HeaderMaxLength > 0 && lint.HeaderMaxLength < commitmsg.SubjectLimit {
+		g.options.SubjectLimit, g.rules.SubjectLimit = lint.HeaderMaxLength, lint.HeaderMaxLength
+	}
+}
+
+// describeAreas formats inferred scopes for the prompt, keeping only allowed ones when a list is given
+func describeAreas(areas []scope.Area, allowed []string) []string {
+	var described []string
+	for _, area := range areas {
+		if len(allowed) > 0 && !slices.Contains(allowed, area.Scope) {
 			continue
 		}
+
+		files := "1 file"
+		if area.Files > 1 {
+			files = fmt.Sprintf("%d files", area.Files)
+		}
+		described = append(described, fmt.Sprintf("%s (%s in %s)", area.Scope, files, area.Dir))
+	}
+	return described
+}
+
+// candidates generates n distinct commit messages
+// A single message uses a low temperature for consistency, alternatives a higher one for variety
+func (g *commitGenerator) candidates(ctx context.Context, n int) ([]string, error) {
+	temperature := 0.3
+	if n > 1 {
+		temperature = 0.8
+		fmt.Printf("Generating %d candidates...\n", n)
+	}
+
+	var candidates []string
+	for i := 0; i < n; i++ {
+		message, err := g.generate(ctx, temperature)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(candidates, message) {
+			candidates = append(candidates, message)
+		}
+	}
+	return candidates, nil
+}
+
+// refine records feedback on a candidate so the next generation takes it into account
+func (g *commitGenerator) refine(candidate, instruction string) {
+	g.feedback = append(g.feedback,
+		ai.Message{Role: "assistant", Content: candidate},
+		ai.Message{Role: "user", Content: prompt.CommitFeedbackPrompt(instruction)},
+	)
+}
+
+// generate asks the AI for a commit message and formats it
+func (g *commitGenerator) generate(ctx context.Context, temperature float64) (string, error) {
+	userPrompt, err := g.changes.userPrompt(ctx, g.client)
+	if err != nil {
+		return "", err
+	}
+
+	messages := []ai.Message{
+		{Role: "system", Content: g.system},
+		{Role: "user", Content: userPrompt},
+	}
+	messages = append(messages, g.feedback...)
+
+	content, err := g.ask(ctx, messages, temperature)
+	if err != nil {
+		return "", err
+	}
+	return g.repair(ctx, messages, content, temperature)
+}
+
+// repair validates content and feeds it back with the problems found, a bounded number of times
+// messages is the conversation that produced content; the result is formatted
+func (g *commitGenerator) repair(ctx context.Context, messages []ai.Message, content string, temperature float64) (string, error) {
+	for attempt := 0; ; attempt++ {
+		problems := g.validate(content)
+		if len(problems) == 0 {
+			return g.format(content), nil
+		}
+		if attempt == commitRepairAttempts {
+			fmt.Fprintf(os.Stderr, "Warning: the commit message does not follow Conventional Commits: %s\n", strings.Join(problems, "; "))
+			return g.format(content), nil
+		}
+
+		messages = append(messages,
+			ai.Message{Role: "assistant", Content: content},
+			ai.Message{Role: "user", Content: prompt.CommitRepairPrompt(problems)},
+		)
+
+		var err error
+		if content, err = g.ask(ctx, messages, temperature); err != nil {
+			return "", err
+		}
+	}
+}
+
+// ask sends messages and returns the text of the reply
+func (g *commitGenerator) ask(ctx context.Context, messages []ai.Message, temperature float64) (string, error) {
+	resp, err := g.client.Chat(ctx, messages, temperature, g.cfg.MaxTokens)
+	if err != nil {
+		return "", fmt.Errorf("AI request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// validate returns the Conventional Commits problems of a generated message, if the standard is in use
+func (g *commitGenerator) validate(text string) []string {
+	if !g.conventional {
+		return nil
+	}
+	return commitmsg.Validate(commitmsg.Parse(commitmsg.StripWrapping(text)), g.rules)
+}
+
+// format normalizes a generated message and adds the issue reference
+func (g *commitGenerator) format(text string) string {
+	msg := commitmsg.Parse(commitmsg.StripWrapping(text))
+	if !g.cfg.Commit.IncludeBody {
+		msg.Body = ""
+	}
+	if g.rules.SubjectLimit > 0 {
+		msg.Subject = commitmsg.ShortenSubject(msg.Subject, g.rules.SubjectLimit)
+	}
+	if g.hasIssue && !msg.HasFooter(g.issue.Token) {
+		msg.AddFooter(g.issue)
+	}
+
+	return msg.String()
+}
+
+// commitDiffBudget returns the token budget for the diff in a single commit request
+func commitDiffBudget(cfg *config.Config) int {
+	budget := inputBudget(cfg)
+	if cfg.Commit.MaxDiffTokens > 0 && cfg.Commit.MaxDiffTokens < budget {
+		budget = cfg.Commit.MaxDiffTokens
 	}
+	return budget
 }
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/diff"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+const (
+	// maxSummaryRequests caps the summary requests for one diff, keeping large refactors clear of rate limits
+	maxSummaryRequests = 8
+
+	// summaryReplyTokens is the reply budget for the summary of a single file
+	summaryReplyTokens = 120
+)
+
+// commitDiff prepares a git diff for the commit prompt within a token budget
+// Diffs that do not fit are summarized in batches of files and the summaries are sent instead
+type commitDiff struct {
+	files     []diff.File
+	compact   string
+	budget    int
+	summaries string // Cached across regenerations
+}
+
+// newCommitDiff parses raw diff output and stubs lockfiles, generated and binary files
+func newCommitDiff(raw string, budget int) *commitDiff {
+	files := diff.Parse(raw)
+	return &commitDiff{
+		files:   files,
+		compact: diff.Render(files),
+		budget:  budget,
+	}
+}
+
+// fits reports whether the compact diff can be sent in a single request
+func (d *commitDiff) fits() bool {
+	return tokens.Estimate(d.compact) <= d.budget
+}
+
+// paths returns the paths of the changed files
+func (d *commitDiff) paths() []string {
+	paths := make([]string, 0, len(d.files))
+	for _, f := range d.files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// stubbed returns the number of files whose contents are omitted
+func (d *commitDiff) stubbed() int {
+	count := 0
+	for _, f := range d.files {
+		if f.Stubbed() {
+			count++
+		}
+	}
+	return count
+}
+
+// userPrompt returns the user prompt for commit generation, summarizing per file first if needed
+func (d *commitDiff) userPrompt(ctx context.Context, client *ai.Client) (string, error) {
+	content, summarized, err := d.content(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	if summarized {
+		return prompt.CommitSummariesUserPrompt(content), nil
+	}
+	return prompt.CommitUserPrompt(content), nil
+}
+
+// content returns the compact diff, or per-file summaries within the budget when the diff does not fit
+func (d *commitDiff) content(ctx context.Context, client *ai.Client) (string, bool, error) {
+	if d.fits() {
+		return d.compact, false, nil
+	}
+
+	if d.summaries == "" {
+		summaries, err := d.summarize(ctx, client)
+		if err != nil {
+			return "", false, err
+		}
+		d.summaries = summaries
+	}
+
+	return diff.Truncate(d.summaries, d.budget), true, nil
+}
+
+// summarize asks the AI to summarize the source files in batches and lists stubbed files as they are
+// Beyond maxSummaryRequests batches, and for batches whose request fails, files are listed with their line counts only
+func (d *commitDiff) summarize(ctx context.Context, client *ai.Client) (string, error) {
+	summaries := map[string]string{}
+	batches := d.batches()
+	if len(batches) > maxSummaryRequests {
+		fmt.Fprintf(os.Stderr, "Warning: the diff would need %d summary requests (limit %d); listing changed files with line counts only\n", len(batches), maxSummaryRequests)
+		batches = nil
+	}
+
+	for i, batch := range batches {
+		fmt.Fprintf(os.Stderr, "Summarizing %d/%d: %d file(s)\n", i+1, len(batches), len(batch))
+
+		resp, err := client.Chat(ctx, d.batchMessages(batch), 0.2, summaryReplyTokens*len(batch))
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: failed to summarize %d file(s), listing them with line counts only: %v\n", len(batch), err)
+			continue
+		}
+		if len(resp.Choices) > 0 {
+			parseSummaries(resp.Choices[0].Message.Content, batch, summaries)
+		}
+	}
+
+	lines := make([]string, 0, len(d.files))
+	for _, f := range d.files {
+		switch summary, ok := summaries[f.Path]; {
+		case f.Stubbed():
+			lines = append(lines, fmt.Sprintf("- %s: %s", f.Path, stubNote(f)))
+		case ok:
+			lines = append(lines, fmt.Sprintf("- %s (+%d -%d): %s", f.Path, f.Added, f.Deleted, summary))
+		default:
+			lines = append(lines, fmt.Sprintf("- %s (+%d -%d)", f.Path, f.Added, f.Deleted))
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// batches groups the source files into as few summary requests as fit the budget, each file truncated to it
+func (d *commitDiff) batches() [][]diff.File {
+	var batches [][]diff.File
+	used := 0
+	for _, f := range d.files {
+		if f.Stubbed() {
+			continue
+		}
+
+		f.Text = diff.Truncate(f.Text, d.budget)
+		cost := tokens.Estimate(f.Text)
+		if len(batches) == 0 || used+cost > d.budget {
+			batches = append(batches, nil)
+			used = 0
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], f)
+		used += cost
+	}
+	return batches
+}
+
+// batchMessages builds the summarization request for a batch of files
+func (d *commitDiff) batchMessages(batch []diff.File) []ai.Message {
+	return []ai.Message{
+		{Role: "system", Content: prompt.CommitFileSummarySystemPrompt()},
+		{Role: "user", Content: prompt.CommitFileSummaryUserPrompt(diff.Render(batch))},
+	}
+}
+
+// parseSummaries reads "path: summary" lines from the reply into summaries, ignoring paths outside batch
+func parseSummaries(reply string, batch []diff.File, summaries map[string]string) {
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "-* ")
+		path, summary, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+
+		path = strings.Trim(path, "`*")
+		summary = strings.Join(strings.Fields(summary), " ")
+		for _, f := range batch {
+			if f.Path == path && summary != "" {
+				summaries[path] = summary
+			}
+		}
+	}
+}
+
+// printDryRun prints the prompts that would be sent and their estimated size
+func (d *commitDiff) printDryRun(systemPrompt string) {
+	if d.fits() {
+		userPrompt := prompt.CommitUserPrompt(d.compact)
+		printPrompt("System prompt", systemPrompt)
+		printPrompt("User prompt", userPrompt)
+		fmt.Printf("Estimated size: ~%d tokens (budget %d, %d file(s), %d stubbed)\n",
+			tokens.Estimate(systemPrompt)+tokens.Estimate(userPrompt), d.budget, len(d.files), d.stubbed())
+		return
+	}
+
+	batches := d.batches()
+	fmt.Printf("Diff is ~%d tokens, over the %d token budget: files are summarized in batches first,\n", tokens.Estimate(d.compact), d.budget)
+	fmt.Println("then the commit message is generated from the summaries.")
+	if len(batches) > maxSummaryRequests {
+		fmt.Printf("The %d batches exceed the limit of %d requests, so files are listed with line counts only.\n", len(batches), maxSummaryRequests)
+		printPrompt("System prompt for the final request", systemPrompt)
+		return
+	}
+
+	total := 0
+	for i, batch := range batches {
+		messages := d.batchMessages(batch)
+		printPrompt(fmt.Sprintf("Summary prompt %d/%d", i+1, len(batches)), messages[1].Content)
+		total += tokens.Estimate(messages[0].Content) + tokens.Estimate(messages[1].Content)
+	}
+	printPrompt("System prompt for the final request", systemPrompt)
+	fmt.Printf("Estimated size of summary requests: ~%d tokens (%d request(s), %d file(s), %d stubbed)\n", total, len(batches), len(d.files)-d.stubbed(), d.stubbed())
+}
+
+// printPrompt prints a titled prompt section
+func printPrompt(title, text string) {
+	fmt.Printf("── %s ──\n%s\n\n", title, text)
+}
+
+// stubNote describes an omitted file for the summary list
+func stubNote(f diff.File) string {
+	if f.Kind == diff.KindBinary {
+		return f.Kind.String() + " changed"
+	}
+	return fmt.Sprintf("%s changed (+%d -%d lines)", f.Kind, f.Added, f.Deleted)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/commitplan"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/diff"
+	"github.com/zarazaex69/zik/apps/cli/internal/editor"
+	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+// commitSplitter groups the changes since HEAD into several commits
+type commitSplitter struct {
+	cfg       *config.Config
+	origins   config.Origins
+	gitClient *git.Client
+	generator *commitGenerator
+	changes   []diff.Change
+}
+
+func runCommitSplit(parent context.Context, cfg *config.Config, origins config.Origins, gitClient *git.Client) error {
+	head, err := gitClient.GetHead()
+	if err != nil {
+		return fmt.Errorf("cannot split changes before the first commit: %w", err)
+	}
+
+	raw, err := gitClient.GetPatchAll()
+	if err != nil {
+		return fmt.Errorf("failed to get git diff: %w", err)
+	}
+	if raw == "" {
+		return fmt.Errorf("no changes to commit")
+	}
+
+	s := &commitSplitter{
+		cfg:       cfg,
+		origins:   origins,
+		gitClient: gitClient,
+		generator: newCommitGenerator(cfg, origins, gitClient, raw),
+		changes:   diff.Changes(diff.Parse(raw)),
+	}
+
+	fmt.Printf("Planning commits for %d changes...\n", len(s.changes))
+
+	reader := bufio.NewReader(os.Stdin)
+
+	// Ctrl-C is only caught while waiting for the AI, so it still exits at the prompt
+	replan := func() (commitplan.Plan, error) {
+		ctx, cancel := interruptContext(parent)
+		defer cancel()
+		return s.plan(ctx)
+	}
+
+	plan, err := replan()
+	if err != nil {
+		return err
+	}
+
+	for {
+		s.printPlan(plan)
+
+		if commitApply {
+			return s.apply(plan, head)
+		}
+
+		fmt.Print("\nApply plan? [Y]es / [N]o / [E]dit / [R]egenerate: ")
+		response, err := readLine(reader)
+		if err != nil {
+			fmt.Println("\nSplit cancelled.")
+			return nil
+		}
+
+		switch strings.ToLower(response) {
+		case "y", "yes", "":
+			return s.apply(plan, head)
+		case "n", "no":
+			fmt.Println("Split cancelled.")
+			return nil
+		case "e", "edit":
+			edited, err := s.edit(plan)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			if len(edited.Groups) == 0 {
+				fmt.Println("Split cancelled.")
+				return nil
+			}
+			plan = edited
+		case "r", "regenerate":
+			fmt.Println("\nRegenerating...")
+			if plan, err = replan(); err != nil {
+				return err
+			}
+		default:
+			fmt.Println("Invalid option. Please choose Y, N, E or R.")
+		}
+	}
+}
+
+// plan asks the AI to group the changes and writes a separate commit for any it left out
+func (s *commitSplitter) plan(ctx context.Context) (commitplan.Plan, error) {
+	messages := []ai.Message{
+		{Role: "system", Content: prompt.WithInstructions(prompt.CommitSplitSystemPrompt(s.generator.options), s.cfg.Instructions)},
+		{Role: "user", Content: prompt.CommitSplitUserPrompt(s.listing())},
+	}
+
+	resp, err := s.generator.client.Chat(ctx, messages, 0.3, s.cfg.MaxTokens)
+	if err != nil {
+		return commitplan.Plan{}, fmt.Errorf("AI request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return commitplan.Plan{}, fmt.Errorf("no response from AI")
+	}
+
+	plan, err := commitplan.ParseResponse(resp.Choices[0].Message.Content, len(s.changes))
+	if err != nil {
+		return commitplan.Plan{}, fmt.Errorf("failed to read commit plan: %w", err)
+	}
+	// Planned messages are held to the same rules as single commits; the repair only needs the commit rules
+	repairContext := []ai.Message{{Role: "system", Content: s.generator.system}}
+	for i := range plan.Groups {
+		message, err := s.generator.repair(ctx, repairContext, plan.Groups[i].Message, 0.3)
+		if err != nil {
+			return commitplan.Plan{}, err
+		}
+		plan.Groups[i].Message = message
+	}
+
+	if missing := plan.Unassigned(len(s.changes)); len(missing) > 0 {
+		fmt.Printf("%d changes were not grouped, writing a commit for them...\n", len(missing))
+		message, err := newCommitGenerator(s.cfg, s.origins, s.gitClient, diff.Patch(s.selected(missing))).generate(ctx, 0.3)
+		if err != nil {
+			return commitplan.Plan{}, err
+		}
+		plan.Append(message, missing)
+	}
+
+	return plan, nil
+}
+
+// listing numbers the changes for the prompt, truncating each to its share of the budget
+func (s *commitSplitter) listing() string {
+	parts := make([]string, 0, len(s.changes))
+	for _, c := range s.changes {
+		parts = append(parts, c.Compact())
+	}
+
+	perChange := commitDiffBudget(s.cfg)
+	if tokens.Estimate(strings.Join(parts, "\n")) > perChange {
+		perChange = shareBudget(perChange, len(s.changes))
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		fmt.Fprintf(&b, "[%d] %s\n%s\n\n", i+1, s.changes[i].Label(), diff.Truncate(part, perChange))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// selected returns the changes with the given IDs
+func (s *commitSplitter) selected(ids []int) []diff.Change {
+	changes := make([]diff.Change, 0, len(ids))
+	for _, id := range ids {
+		changes = append(changes, s.changes[id-1])
+	}
+	return changes
+}
+
+// label describes the change with the given ID
+func (s *commitSplitter) label(id int) string {
+	return s.changes[id-1].Label()
+}
+
+// printPlan shows the planned commits and the changes left out of them
+func (s *commitSplitter) printPlan(plan commitplan.Plan) {
+	for i, g := range plan.Groups {
+		fmt.Printf("\n%s\n%s\n", render.Bold(fmt.Sprintf("Commit %d/%d", i+1, len(plan.Groups))), g.Message)
+		for _, id := range g.Changes {
+			fmt.Println(render.Dim(fmt.Sprintf("  [%d] %s", id, s.label(id))))
+		}
+	}
+
+	if missing := plan.Unassigned(len(s.changes)); len(missing) > 0 {
+		fmt.Printf("\n%s\n", render.Bold("Left uncommitted"))
+		for _, id := range missing {
+			fmt.Println(render.Dim(fmt.Sprintf("  [%d] %s", id, s.label(id))))
+		}
+	}
+}
+
+// edit lets the user reorder, regroup and reword the plan in their editor
+func (s *commitSplitter) edit(plan commitplan.Plan) (commitplan.Plan, error) {
+	text, err := editor.Edit(plan.Format(s.label), "zik-split-*.txt")
+	if err != nil {
+		return plan, err
+	}
+
+	edited, err := commitplan.ParseEdited(text, len(s.changes))
+	if err != nil {
+		return plan, fmt.Errorf("invalid plan, keeping the previous one: %w", err)
+	}
+	return edited, nil
+}
+
+// apply stages and commits each group in turn on top of a clean index
+// On failure the commits made so far are undone and the original index is restored;
+// the working tree is never modified
+func (s *commitSplitter) apply(plan commitplan.Plan, head string) error {
+	index, err := s.gitClient.WriteTree()
+	if err != nil {
+		return err
+	}
+
+	abort := func(step error) error {
+		restoreErr := errors.Join(s.gitClient.ResetSoft(head), s.gitClient.ReadTree(index))
+		if restoreErr != nil {
+			return fmt.Errorf("%w; restoring the original state also failed: %v", step, restoreErr)
+		}
+		return fmt.Errorf("%w; HEAD and the index were restored", step)
+	}
+
+	if err := s.gitClient.ReadTree(head); err != nil {
+		return abort(err)
+	}
+
+	for i, g := range plan.Groups {
+		if err := s.gitClient.ApplyCached(diff.Patch(s.selected(g.Changes))); err != nil {
+			return abort(fmt.Errorf("commit %d: %w", i+1, err))
+		}
+		if err := s.gitClient.Commit(g.Message); err != nil {
+			return abort(fmt.Errorf("commit %d: %w", i+1, err))
+		}
+
+		subject, _, _ := strings.Cut(g.Message, "\n")
+		fmt.Printf("Committed %d/%d: %s\n", i+1, len(plan.Groups), subject)
+	}
+
+	fmt.Printf("Created %d commits.\n", len(plan.Groups))
+	return nil
+}
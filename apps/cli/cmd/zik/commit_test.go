@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/diff"
+)
+
+func TestConventionalStyle(t *testing.T) {
+	plain := commitmsg.Style{Sampled: 10}
+	conventional := commitmsg.Style{Sampled: 10, Conventional: true}
+	tests := []struct {
+		name       string
+		configured bool
+		origin     config.Origin
+		style      commitmsg.Style
+		want       bool
+	}{
+		{"default follows plain history", true, config.Origin{Layer: config.LayerDefault}, plain, false},
+		{"default follows conventional history", true, config.Origin{Layer: config.LayerDefault}, conventional, true},
+		{"default without history", true, config.Origin{Layer: config.LayerDefault}, commitmsg.Style{}, true},
+		{"configured true wins", true, config.Origin{Layer: config.LayerProject}, plain, true},
+		{"configured false wins", false, config.Origin{Layer: config.LayerGlobal}, conventional, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conventionalStyle(tt.configured, tt.origin, tt.style); got != tt.want {
+				t.Errorf("conventionalStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseCandidate(t *testing.T) {
+	candidates := []string{"feat: add login", "feat: support login"}
+	tests := []struct {
+		name       string
+		input      string
+		wantPick   string
+		wantAction string
+	}{
+		{"pick", "2\n", "feat: support login", "pick"},
+		{"last line without newline", "1", "feat: add login", "pick"},
+		{"regenerate", "r\n", "", "regenerate"},
+		{"no", "n\n", "", "cancel"},
+		{"end of input", "", "", "cancel"},
+		{"invalid then end of input", "7\nmaybe\n", "", "cancel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pick, action := chooseCandidate(bufio.NewReader(strings.NewReader(tt.input)), candidates)
+			if pick != tt.wantPick || action != tt.wantAction {
+				t.Errorf("chooseCandidate() = %q, %q, want %q, %q", pick, action, tt.wantPick, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestCommitDiffBatches(t *testing.T) {
+	var raw strings.Builder
+	for i := range 6 {
+		fmt.Fprintf(&raw, "diff --git a/f%d.go b/f%d.go\n--- a/f%d.go\n+++ b/f%d.go\n@@ -1,0 +1,20 @@\n", i, i, i, i)
+		for j := range 20 {
+			fmt.Fprintf(&raw, "+line %d\n", j)
+		}
+	}
+	raw.WriteString("diff --git a/go.sum b/go.sum\n--- a/go.sum\n+++ b/go.sum\n@@ -1,0 +1,1 @@\n+x v1 h1:abc\n")
+
+	d := newCommitDiff(raw.String(), 150)
+	batches := d.batches()
+	if len(batches) < 2 || len(batches) >= 6 {
+		t.Fatalf("batches() returned %d batches, want several files per batch", len(batches))
+	}
+
+	files := 0
+	for _, batch := range batches {
+		for _, f := range batch {
+			if f.Stubbed() {
+				t.Errorf("batches() included stubbed file %s", f.Path)
+			}
+			files++
+		}
+	}
+	if files != 6 {
+		t.Errorf("batches() covered %d files, want 6", files)
+	}
+}
+
+func TestParseSummaries(t *testing.T) {
+	batch := []diff.File{{Path: "a.go"}, {Path: "b/c.go"}}
+	reply := "Here you go:\n- `a.go`: adds   the parser\n* b/c.go: renames Load\nother.go: not asked for"
+
+	summaries := map[string]string{}
+	parseSummaries(reply, batch, summaries)
+
+	if summaries["a.go"] != "adds the parser" || summaries["b/c.go"] != "renames Load" {
+		t.Errorf("parseSummaries() = %v", summaries)
+	}
+	if _, ok := summaries["other.go"]; ok {
+		t.Error("parseSummaries() kept a path outside the batch")
+	}
+}
+
+func TestCommitGeneratorRepair(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"fix(api): handle empty input"}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Provider = "test"
+	cfg.Providers = map[string]config.ProviderConfig{"test": {Endpoint: server.URL}}
+	g := &commitGenerator{cfg: cfg, client: ai.NewClient(cfg), conventional: true}
+	system := []ai.Message{{Role: "system", Content: "rules"}}
+
+	// A valid message is kept without asking the AI
+	message, err := g.repair(context.Background(), system, "feat: add login", 0.3)
+	if err != nil || message != "feat: add login" || requests != 0 {
+		t.Errorf("repair() = %q, %v after %d requests, want the message unchanged", message, err, requests)
+	}
+
+	// A made-up type is sent back with the problems found
+	message, err = g.repair(context.Background(), system, "oops: handle empty input", 0.3)
+	if err != nil || message != "fix(api): handle empty input" || requests != 1 {
+		t.Errorf("repair() = %q, %v after %d requests, want the repaired message after 1", message, err, requests)
+	}
+}
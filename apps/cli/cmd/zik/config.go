@@ -2,9 +2,13 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/editor"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,43 +16,106 @@ var (
 	configCmd = &cobra.Command{
 		Use:   "config",
 		Short: "Manage ZIK configuration",
-		Long:  `View and modify ZIK configuration settings.`,
+		Long: `View and modify ZIK configuration settings.
+Keys use dotted paths that mirror the YAML file, e.g. commit.preferred_type.`,
 	}
 
 	configListCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List all configuration values",
-		RunE:  runConfigList,
+		Long: `List the effective configuration.
+Values are merged from the defaults, ~/.config/zik/config.yaml, the nearest .zik.yaml
+in the current directory or its parents, ZIK_* environment variables and flags,
+in increasing order of precedence.`,
+		Example: `  zik config list
+  zik config list --show-origin`,
+		RunE: runConfigList,
 	}
 
 	configGetCmd = &cobra.Command{
 		Use:   "get <key>",
 		Short: "Get a configuration value",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runConfigGet,
+		Long: `Print the value of a configuration key.
+Naming a section such as "commit" prints the whole section.
+
+Available keys:
+  ` + strings.Join(config.Keys(), "\n  "),
+		Example: `  zik config get model
+  zik config get commit.preferred_type
+  zik config get chat`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runConfigGet,
 	}
 
 	configSetCmd = &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configuration value",
-		Args:  cobra.ExactArgs(2),
-		RunE:  runConfigSet,
+		Long: `Set a configuration key and save it to the global config file.
+Values are parsed according to the key type and validated before saving.
+
+Available keys:
+  ` + strings.Join(config.Keys(), "\n  "),
+		Example: `  zik config set temperature 0.3
+  zik config set commit.conventional_commits false
+  zik config set chat.timeout 2m`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE:         runConfigSet,
+	}
+
+	configUnsetCmd = &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Reset a configuration value to its default",
+		Long: `Reset a configuration key, or a whole section, to its default value
+and save the config file.`,
+		Example: `  zik config unset temperature
+  zik config unset chat`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runConfigUnset,
+	}
+
+	configEditCmd = &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in your editor",
+		Long: `Open the config file in $VISUAL or $EDITOR.
+The file is created with default values if it does not exist yet,
+and is validated after the editor exits.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runConfigEdit,
 	}
 )
 
+var configShowOrigin bool
+
 func init() {
+	configListCmd.Flags().BoolVar(&configShowOrigin, "show-origin", false, "Show where each value comes from")
+
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
 }
 
 func runConfigList(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	cfg, origins, err := loadConfigWithOrigins(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	data, err := yaml.Marshal(cfg)
+	if configShowOrigin {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, key := range config.Keys() {
+			value, _ := cfg.Get(key)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, origins[key])
+		}
+		return w.Flush()
+	}
+
+	data, err := yaml.Marshal(cfg.Redacted())
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -61,11 +128,111 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 }
 
 func runConfigGet(cmd *cobra.Command, args []string) error {
-	// TODO: Implement config get
-	return fmt.Errorf("not implemented yet")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
 }
 
 func runConfigSet(cmd *cobra.Command, args []string) error {
-	// TODO: Implement config set
-	return fmt.Errorf("not implemented yet")
+	key, value := args[0], args[1]
+
+	// Only the global file is rewritten, so project and env overrides must not be merged in
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	saved, _ := cfg.Get(key)
+	fmt.Printf("%s = %s\n", key, saved)
+	warnOverridden(cmd, key)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Unset(key); err != nil {
+		return err
+	}
+
+	// Resetting one key can still conflict with the others, e.g. a profile naming a removed provider
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("cannot reset %s: %w", key, err)
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("%s reset to default\n", key)
+	warnOverridden(cmd, key)
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return fmt.Errorf("failed to locate config file: %w", err)
+	}
+
+	// Start from the defaults so the user sees every available setting
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := config.Default().Save(); err != nil {
+			return fmt.Errorf("failed to create config file: %w", err)
+		}
+	}
+
+	if err := editor.Open(path); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("config file %s is not valid YAML: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("config file %s is invalid: %w", path, err)
+	}
+
+	fmt.Println("Configuration saved.")
+	return nil
+}
+
+// warnOverridden tells the user when a key just written to the global file is shadowed by a higher layer
+func warnOverridden(cmd *cobra.Command, key string) {
+	_, origins, err := loadConfigWithOrigins(cmd)
+	if err != nil {
+		return
+	}
+
+	for _, k := range config.Keys() {
+		if k != key && !strings.HasPrefix(k, key+".") {
+			continue
+		}
+		if origin := origins[k]; origin.Layer > config.LayerGlobal {
+			fmt.Fprintf(os.Stderr, "Note: %s is overridden by %s\n", k, origin)
+		}
+	}
 }
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+var (
+	profileProvider     string
+	profileModel        string
+	profileTemperature  float64
+	profileMaxTokens    int
+	profileStreaming    bool
+	profileThinking     bool
+	profileInstructions string
+	profileUse          bool
+
+	configProfileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named configuration profiles",
+		Long: `Profiles are named sets of model and prompt settings stored in the config file.
+Select one per invocation with --profile or ZIK_PROFILE, or make it the default with "zik config profile use".`,
+	}
+
+	configProfileListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List profiles, marking the active one",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigProfileList,
+	}
+
+	configProfileUseCmd = &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a profile the default",
+		Long: `Make a profile the default for every command.
+Run "zik config unset profile" to go back to the plain configuration.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runConfigProfileUse,
+	}
+
+	configProfileCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile",
+		Long: `Create a profile in the global config file.
+Only the settings given as flags are stored; everything else falls through to the regular configuration.`,
+		Example: `  zik config profile create fast --model GLM-4-6-API-V1 --temperature 0.2 --max-tokens 500
+  zik config profile create deep --thinking --max-tokens 8000 --instructions "Think step by step" --use
+
+  # --provider names a provider defined in ~/.config/zik/config.yaml, such as
+  #   providers:
+  #     ollama:
+  #       protocol: ollama
+  zik config profile create offline --provider ollama --model llama3`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runConfigProfileCreate,
+	}
+)
+
+func init() {
+	flags := configProfileCreateCmd.Flags()
+	flags.StringVar(&profileProvider, "provider", "", "Provider to send requests to")
+	flags.StringVar(&profileModel, "model", "", "Model to use")
+	flags.Float64Var(&profileTemperature, "temperature", 0, "Sampling temperature (0-2)")
+	flags.IntVar(&profileMaxTokens, "max-tokens", 0, "Maximum tokens per reply")
+	flags.BoolVar(&profileStreaming, "streaming", true, "Stream responses")
+	flags.BoolVar(&profileThinking, "thinking", false, "Ask the model to reason before answering")
+	flags.StringVar(&profileInstructions, "instructions", "", "Extra instructions added to every system prompt")
+	flags.BoolVar(&profileUse, "use", false, "Make the new profile the default")
+
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileUseCmd)
+	configProfileCmd.AddCommand(configProfileCreateCmd)
+	configCmd.AddCommand(configProfileCmd)
+}
+
+func runConfigProfileList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := cfg.ProfileNames()
+	if len(names) == 0 {
+		fmt.Println("No profiles. Create one with: zik config profile create <name>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		marker := " "
+		if name == cfg.Profile {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", marker, name, describeProfile(cfg.Profiles[name]))
+	}
+	return w.Flush()
+}
+
+func runConfigProfileUse(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Set("profile", args[0]); err != nil {
+		return err
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Using profile %s\n", args[0])
+	warnOverridden(cmd, "profile")
+	return nil
+}
+
+func runConfigProfileCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if name == "" || strings.ContainsAny(name, ". \t") {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if _, exists := cfg.Profiles[name]; exists {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	// Store only what was asked for, so the profile does not pin unrelated defaults
+	flags := cmd.Flags()
+	profile := config.Profile{
+		Provider:     profileProvider,
+		Model:        profileModel,
+		MaxTokens:    profileMaxTokens,
+		Instructions: profileInstructions,
+	}
+	if flags.Changed("temperature") {
+		profile.Temperature = &profileTemperature
+	}
+	if flags.Changed("streaming") {
+		profile.Streaming = &profileStreaming
+	}
+	if flags.Changed("thinking") {
+		profile.Thinking = &profileThinking
+	}
+
+	if err := profile.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	if _, err := cfg.LookupProvider(profile.Provider); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]config.Profile{}
+	}
+	cfg.Profiles[name] = profile
+	if profileUse {
+		cfg.Profile = name
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Created profile %s: %s\n", name, describeProfile(profile))
+	if profileUse {
+		fmt.Printf("Using profile %s\n", name)
+		warnOverridden(cmd, "profile")
+	}
+	return nil
+}
+
+// describeProfile summarizes the settings a profile overrides
+func describeProfile(p config.Profile) string {
+	var parts []string
+	if p.Provider != "" {
+		parts = append(parts, "provider="+p.Provider)
+	}
+	if p.Model != "" {
+		parts = append(parts, "model="+p.Model)
+	}
+	if p.Temperature != nil {
+		parts = append(parts, "temperature="+strconv.FormatFloat(*p.Temperature, 'g', -1, 64))
+	}
+	if p.MaxTokens != 0 {
+		parts = append(parts, "max_tokens="+strconv.Itoa(p.MaxTokens))
+	}
+	if p.Streaming != nil {
+		parts = append(parts, "streaming="+strconv.FormatBool(*p.Streaming))
+	}
+	if p.Thinking != nil {
+		parts = append(parts, "thinking="+strconv.FormatBool(*p.Thinking))
+	}
+	if p.Instructions != "" {
+		parts = append(parts, "instructions="+strconv.Quote(p.Instructions))
+	}
+
+	if len(parts) == 0 {
+		return "(no overrides)"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+func TestConfigProfileCreate_Thinking(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	rootCmd.SetArgs([]string{"config", "profile", "create", "deep", "--thinking", "--max-tokens", "8000"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("profile create error = %v", err)
+	}
+
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	profile := cfg.Profiles["deep"]
+	if profile.Thinking == nil || !*profile.Thinking || profile.MaxTokens != 8000 {
+		t.Errorf("stored profile = %+v, want thinking true and max_tokens 8000", profile)
+	}
+	if profile.Streaming != nil {
+		t.Error("stored profile should not pin streaming, which was not given")
+	}
+}
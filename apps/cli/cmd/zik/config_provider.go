@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+var (
+	configProviderCmd = &cobra.Command{
+		Use:   "provider",
+		Short: "Manage AI providers",
+		Long: `Providers are the backends that serve AI requests: the hosted ZIK API ("zik", the default),
+a self-hosted zik-server or any other OpenAI-compatible endpoint, or Ollama.
+They are defined under "providers" in the config file and selected with --provider, ZIK_PROVIDER,
+a profile or "zik config provider use".`,
+	}
+
+	configProviderListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List providers, marking the active one",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigProviderList,
+	}
+
+	configProviderUseCmd = &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a provider the default",
+		Long: `Make a provider the default for every command.
+Run "zik config unset provider" to go back to the hosted ZIK API.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runConfigProviderUse,
+	}
+)
+
+func init() {
+	configProviderCmd.AddCommand(configProviderListCmd)
+	configProviderCmd.AddCommand(configProviderUseCmd)
+	configCmd.AddCommand(configProviderCmd)
+}
+
+func runConfigProviderList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	active := cfg.Provider
+	if active == "" {
+		active = config.DefaultProvider
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range cfg.ProviderNames() {
+		provider, err := cfg.LookupProvider(name)
+		if err != nil {
+			return err
+		}
+
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		models := strings.Join(provider.Models, ", ")
+		if models == "" {
+			models = "-"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\n", marker, name, provider.Protocol, provider.Endpoint, models)
+	}
+	return w.Flush()
+}
+
+func runConfigProviderUse(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadGlobal()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Set("provider", args[0]); err != nil {
+		return err
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Using provider %s\n", args[0])
+	warnOverridden(cmd, "provider")
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/hook"
+)
+
+var (
+	hookChain bool
+
+	hookCmd = &cobra.Command{
+		Use:   "hook",
+		Short: "Manage the git prepare-commit-msg hook",
+		Long: `Install a prepare-commit-msg hook so that "git commit" opens the editor
+with a generated message already filled in.
+The hook only runs for plain "git commit": messages given with -m or -F, templates,
+merges, squashes and amends are left untouched. Failures never block a commit.`,
+	}
+
+	hookInstallCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Install the prepare-commit-msg hook",
+		Long: `Install the prepare-commit-msg hook into the repository hooks directory (core.hooksPath is honoured).
+An existing hook is kept: it is renamed to prepare-commit-msg.pre-zik and runs before zik.
+Use --chain=false to refuse instead.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runHookInstall,
+	}
+
+	hookUninstallCmd = &cobra.Command{
+		Use:          "uninstall",
+		Short:        "Remove the prepare-commit-msg hook and restore a previous one",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runHookUninstall,
+	}
+
+	hookStatusCmd = &cobra.Command{
+		Use:          "status",
+		Short:        "Show whether the hook is installed",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runHookStatus,
+	}
+)
+
+func init() {
+	hookInstallCmd.Flags().BoolVar(&hookChain, "chain", true, "Keep an existing hook and run it before zik")
+
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookUninstallCmd)
+	hookCmd.AddCommand(hookStatusCmd)
+}
+
+// hooksDir returns the hooks directory of the current repository
+func hooksDir() (string, error) {
+	gitClient := git.NewClient()
+	if !gitClient.IsRepository() {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return gitClient.HooksDir()
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	// Call this binary directly so the hook works even where PATH differs, e.g. in GUI clients
+	zikPath, err := os.Executable()
+	if err != nil {
+		zikPath = "zik"
+	} else if resolved, err := filepath.EvalSymlinks(zikPath); err == nil {
+		zikPath = resolved
+	}
+
+	status, err := hook.Install(dir, zikPath, hookChain)
+	if errors.Is(err, hook.ErrForeignHook) {
+		return fmt.Errorf("%w: %s (drop --chain=false to keep it and run it before zik)", err, status.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed %s\n", status.Path)
+	if status.Chained != "" {
+		fmt.Printf("Existing hook kept as %s and runs before zik\n", status.Chained)
+	}
+	return nil
+}
+
+func runHookUninstall(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	before, err := hook.Inspect(dir)
+	if err != nil {
+		return err
+	}
+
+	status, err := hook.Uninstall(dir)
+	if errors.Is(err, hook.ErrForeignHook) {
+		return fmt.Errorf("%w: %s, leaving it in place", err, status.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !before.Installed {
+		fmt.Println("Hook is not installed.")
+		return nil
+	}
+
+	fmt.Printf("Removed %s\n", status.Path)
+	if before.Chained != "" {
+		fmt.Printf("Restored the previous hook from %s\n", before.Chained)
+	}
+	return nil
+}
+
+func runHookStatus(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir()
+	if err != nil {
+		return err
+	}
+
+	status, err := hook.Inspect(dir)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Installed:
+		fmt.Printf("Installed: %s\n", status.Path)
+		if status.Chained != "" {
+			fmt.Printf("Runs first: %s\n", status.Chained)
+		}
+	case status.Foreign:
+		fmt.Printf("Not installed: %s exists but is not managed by zik\n", status.Path)
+	default:
+		fmt.Printf("Not installed (hooks directory: %s)\n", dir)
+	}
+	return nil
+}
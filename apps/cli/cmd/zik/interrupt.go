@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+)
+
+// errInterrupted is returned when the user pressed Ctrl-C during a request
+var errInterrupted = errors.New("interrupted")
+
+// interruptContext returns a context that is cancelled by the first Ctrl-C
+// Only the first one is caught, so a second Ctrl-C terminates the process as usual.
+// Interactive commands create one per request to keep Ctrl-C at their prompts working
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			cancel(errInterrupted)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel(context.Canceled)
+		})
+	}
+}
+
+// interrupted reports whether ctx was cancelled by Ctrl-C
+func interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errInterrupted)
+}
+
+// printInterrupted marks output cut short by Ctrl-C
+func printInterrupted() {
+	fmt.Fprintln(os.Stderr, render.Dim("[interrupted]"))
+}
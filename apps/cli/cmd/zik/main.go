@@ -1,7 +1,8 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -16,12 +17,26 @@ var (
 		Long: `ZIK is a powerful CLI tool that brings AI assistance directly to your terminal.
 Generate commit messages, chat with AI, review code, and more.`,
 		Version: config.Version,
+		// Errors are printed by main, which reports Ctrl-C differently
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			tracker.start(cmd)
+		},
 	}
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+	err := rootCmd.Execute()
+	if flagUsage {
+		tracker.print()
+	}
+	if err != nil {
+		// Requests are only cancelled by Ctrl-C; timeouts surface as deadline errors
+		if errors.Is(err, errInterrupted) || errors.Is(err, context.Canceled) {
+			printInterrupted()
+			os.Exit(130)
+		}
+		printError(err)
 		os.Exit(1)
 	}
 }
@@ -29,8 +44,13 @@ func main() {
 func init() {
 	// Add subcommands
 	rootCmd.AddCommand(commitCmd)
+	rootCmd.AddCommand(prCmd)
+	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(hookCmd)
 	rootCmd.AddCommand(chatCmd)
+	rootCmd.AddCommand(sessionsCmd)
 	rootCmd.AddCommand(askCmd)
 	rootCmd.AddCommand(codeCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(usageCmd)
 }
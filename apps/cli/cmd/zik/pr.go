@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/git"
+	"github.com/zarazaex69/zik/apps/cli/internal/pr"
+	"github.com/zarazaex69/zik/apps/cli/internal/prompt"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+var (
+	prBase     string
+	prOutput   string
+	prBodyOnly bool
+
+	prCmd = &cobra.Command{
+		Use:   "pr",
+		Short: "Draft a pull request title and description",
+		Long: `Draft a pull request title and Markdown description for the current branch.
+The commits between the base branch and HEAD and their combined diff are sent to the model.
+The body has summary, changes, testing and risk sections, or follows the headings of the
+repository's pull request template when .github contains one.`,
+		Example: `  zik pr                          # Compare with the default branch
+  zik pr --base develop           # Compare with another base branch
+  zik pr -o pr.md                 # Write the description to a file
+
+  # Open the pull request with the GitHub CLI
+  zik pr -o pr.md && gh pr create --title "$(head -n 1 pr.md)" --body "$(tail -n +3 pr.md)"`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runPR,
+	}
+)
+
+func init() {
+	prCmd.Flags().StringVarP(&prBase, "base", "b", "", "Base branch to compare with (default: origin's default branch, main or master)")
+	prCmd.Flags().StringVarP(&prOutput, "output", "o", "", "Write the title and description to a file instead of stdout")
+	prCmd.Flags().BoolVar(&prBodyOnly, "body-only", false, "Print only the description, leaving out the title")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	// Load configuration
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	gitClient := git.NewClient()
+	if !gitClient.IsRepository() {
+		return fmt.Errorf("not a git repository")
+	}
+
+	base := prBase
+	if base == "" {
+		if base, err = gitClient.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+
+	commits, err := gitClient.GetLogRange(base + "..HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get git log: %w", err)
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits between %s and HEAD", base)
+	}
+
+	// Three dots compare with the merge base, so changes made on base since branching are left out
+	raw, err := gitClient.GetDiffRange(base + "...HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get git diff: %w", err)
+	}
+
+	template := pr.Template{}
+	if root, err := gitClient.TopLevel(); err == nil {
+		var found bool
+		template, found, err = pr.FindTemplate(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring pull request template: %v\n", err)
+		} else if found {
+			fmt.Fprintf(os.Stderr, "Using template %s\n", template.Path)
+		}
+	}
+
+	system := prompt.WithInstructions(prompt.PRSystemPrompt(template.Content), cfg.Instructions)
+
+	// The commit messages share the input budget with the diff
+	budget := inputBudget(cfg) - tokens.Estimate(system) - tokens.Estimate(strings.Join(commits, "\n"))
+	changes := newCommitDiff(raw, max(budget, minShareTokens))
+
+	fmt.Fprintf(os.Stderr, "Drafting pull request for %d commits against %s...\n", len(commits), base)
+
+	aiClient := newAIClient(cfg)
+	ctx, cancel := interruptContext(cmd.Context())
+	defer cancel()
+
+	content, _, err := changes.content(ctx, aiClient)
+	if err != nil {
+		return err
+	}
+
+	messages := []ai.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: prompt.PRUserPrompt(base, commits, content)},
+	}
+
+	resp, err := aiClient.Chat(ctx, messages, 0.3, cfg.MaxTokens)
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("no response from AI")
+	}
+
+	title, body := pr.Parse(resp.Choices[0].Message.Content)
+	if title == "" {
+		return fmt.Errorf("AI returned an empty pull request")
+	}
+	if missing := missingHeadings(template.Content, body); len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: the description leaves out template sections: %s\n", strings.Join(missing, ", "))
+	}
+
+	text := pr.Format(title, body)
+	if prBodyOnly {
+		text = body + "\n"
+	}
+	if prOutput == "" {
+		fmt.Print(text)
+		return nil
+	}
+
+	if err := os.WriteFile(prOutput, []byte(text), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", prOutput, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", prOutput)
+	return nil
+}
+
+// missingHeadings returns the template headings that do not appear in the body
+func missingHeadings(template, body string) []string {
+	present := map[string]bool{}
+	for _, heading := range pr.Headings(body) {
+		present[strings.ToLower(heading)] = true
+	}
+
+	var missing []string
+	for _, heading := range pr.Headings(template) {
+		if !present[strings.ToLower(heading)] {
+			missing = append(missing, heading)
+		}
+	}
+	return missing
+}
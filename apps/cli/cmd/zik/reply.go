@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+// printReply sends messages to the AI, prints the rendered reply and returns its raw text
+// Temperature, reply length, streaming and reasoning display follow cfg
+func printReply(ctx context.Context, client *ai.Client, messages []ai.Message, cfg *config.Config) (string, error) {
+	reasoning := &reasoningView{show: cfg.ShowReasoning}
+	if cfg.Streaming {
+		return streamReply(ctx, client, messages, cfg.Temperature, cfg.MaxTokens, reasoning)
+	}
+	return completeReply(ctx, client, messages, cfg.Temperature, cfg.MaxTokens, reasoning)
+}
+
+// streamReply streams the reply through the markdown renderer as chunks arrive
+func streamReply(ctx context.Context, client *ai.Client, messages []ai.Message, temperature float64, maxTokens int, reasoning *reasoningView) (string, error) {
+	renderer := render.NewMarkdownRenderer()
+	chunkChan, errChan := client.ChatStream(ctx, messages, temperature, maxTokens)
+
+	var reply strings.Builder
+	for chunk := range chunkChan {
+		reasoning.write(chunk.Reasoning)
+		if chunk.Content != "" {
+			reasoning.close()
+			reply.WriteString(chunk.Content)
+			fmt.Print(renderer.ProcessChunk(chunk.Content))
+		}
+	}
+	reasoning.close()
+
+	// Flush any remaining buffered content
+	if remaining := renderer.Flush(); remaining != "" {
+		fmt.Print(remaining)
+	}
+	fmt.Println() // New line at end
+
+	// Keep what arrived before Ctrl-C; callers decide whether to use it
+	if interrupted(ctx) {
+		return reply.String(), errInterrupted
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return reply.String(), fmt.Errorf("AI request timed out: %w", ctx.Err())
+	}
+
+	// The error channel is closed before the chunk channel, so this never blocks
+	if err := <-errChan; err != nil {
+		return reply.String(), fmt.Errorf("AI request failed: %w", err)
+	}
+
+	return reply.String(), nil
+}
+
+// completeReply waits for the whole reply and renders it at once
+func completeReply(ctx context.Context, client *ai.Client, messages []ai.Message, temperature float64, maxTokens int, reasoning *reasoningView) (string, error) {
+	resp, err := client.Chat(ctx, messages, temperature, maxTokens)
+	if interrupted(ctx) {
+		return "", errInterrupted
+	}
+	if err != nil {
+		return "", fmt.Errorf("AI request failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+
+	reasoning.write(resp.Choices[0].Message.Reasoning)
+	reasoning.close()
+
+	content := resp.Choices[0].Message.Content
+	renderer := render.NewMarkdownRenderer()
+	fmt.Print(renderer.ProcessChunk(content))
+	fmt.Println(renderer.Flush())
+
+	return content, nil
+}
+
+// reasoningView prints the model's reasoning before the answer
+// Shown reasoning is printed dimmed as it arrives; hidden reasoning collapses into a one-line note
+type reasoningView struct {
+	show   bool
+	tokens int  // Estimated size of the reasoning so far
+	closed bool // The answer has started; later reasoning is ignored
+}
+
+// write adds a piece of reasoning
+func (v *reasoningView) write(text string) {
+	if text == "" || v.closed {
+		return
+	}
+	if v.show && v.tokens == 0 {
+		fmt.Println(render.Dim("▾ Reasoning"))
+	}
+	v.tokens += tokens.Estimate(text)
+	if v.show {
+		fmt.Print(dimLines(text))
+	}
+}
+
+// close ends the reasoning section once the answer starts; later calls do nothing
+func (v *reasoningView) close() {
+	if v.closed {
+		return
+	}
+	v.closed = true
+	if v.tokens == 0 {
+		return
+	}
+	if v.show {
+		fmt.Print("\n\n")
+		return
+	}
+	fmt.Println(render.Dim(fmt.Sprintf("▸ Reasoning hidden (~%d tokens, --show-reasoning to expand)", v.tokens)))
+	fmt.Println()
+}
+
+// dimLines dims text line by line, so the style never spans a line break
+func dimLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = render.Dim(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printError prints err with a hint on what to do about failed API requests
+func printError(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+
+	var hint string
+	switch {
+	case errors.Is(err, ai.ErrRateLimited):
+		hint = "The API limits how many requests can be made per minute; wait a moment and try again."
+	case errors.Is(err, ai.ErrAuth):
+		hint = "Check the provider's endpoint and API key (zik config provider list)."
+	case errors.Is(err, ai.ErrUpstream):
+		hint = "The AI service is having trouble; try again later."
+	case errors.Is(err, ai.ErrValidation):
+		hint = "The request was rejected; if the input is large, try sending less of it."
+	}
+	if hint != "" {
+		fmt.Fprintln(os.Stderr, render.Dim(hint))
+	}
+}
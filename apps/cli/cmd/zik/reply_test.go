@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+func TestStreamReply_TimeoutKeepsPartialReply(t *testing.T) {
+	// The server sends one chunk and then stalls until the client gives up
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Partial answer\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Providers = map[string]config.ProviderConfig{"test": {Endpoint: server.URL}}
+	cfg.Provider = "test"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	reply, err := streamReply(ctx, ai.NewClient(cfg), []ai.Message{{Role: "user", Content: "hi"}}, 0.7, 100, &reasoningView{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("streamReply() error = %v, want a deadline error", err)
+	}
+	if reply != "Partial answer" {
+		t.Errorf("streamReply() = %q, want the partial reply", reply)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/session"
+)
+
+var (
+	sessionsExportFormat string
+	sessionsExportOutput string
+
+	sessionsCmd = &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage saved chat sessions",
+		Long:  `List, inspect, delete and export chat sessions saved by "zik chat".`,
+	}
+
+	sessionsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List saved chat sessions",
+		Args:  cobra.NoArgs,
+		RunE:  runSessionsList,
+	}
+
+	sessionsShowCmd = &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a saved chat session",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSessionsShow,
+	}
+
+	sessionsRmCmd = &cobra.Command{
+		Use:     "rm <id>...",
+		Aliases: []string{"delete"},
+		Short:   "Delete saved chat sessions",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runSessionsRm,
+	}
+
+	sessionsExportCmd = &cobra.Command{
+		Use:   "export <id>",
+		Short: "Export a chat session to Markdown or JSON",
+		Example: `  zik sessions export 20260101 > debugging.md
+  zik sessions export 20260101 --format json --output session.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSessionsExport,
+	}
+)
+
+func init() {
+	sessionsExportCmd.Flags().StringVarP(&sessionsExportFormat, "format", "f", "markdown", "Export format (markdown, json)")
+	sessionsExportCmd.Flags().StringVarP(&sessionsExportOutput, "output", "o", "", "Write to file instead of stdout")
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsShowCmd)
+	sessionsCmd.AddCommand(sessionsRmCmd)
+	sessionsCmd.AddCommand(sessionsExportCmd)
+}
+
+// loadSessionStore loads configuration and opens the session store
+func loadSessionStore(cmd *cobra.Command) (*session.Store, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return openSessionStore(cfg)
+}
+
+func runSessionsList(cmd *cobra.Command, args []string) error {
+	store, err := loadSessionStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No saved sessions.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUPDATED\tEXCHANGES\tTITLE")
+	for _, sess := range sessions {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n",
+			sess.ID,
+			sess.UpdatedAt.Local().Format("2006-01-02 15:04"),
+			sess.Exchanges(),
+			sess.DeriveTitle(),
+		)
+	}
+	return w.Flush()
+}
+
+func runSessionsShow(cmd *cobra.Command, args []string) error {
+	store, err := loadSessionStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	sess, err := store.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	renderer := render.NewMarkdownRenderer()
+	fmt.Print(renderer.ProcessChunk(session.ExportMarkdown(sess)))
+	fmt.Println(renderer.Flush())
+
+	return nil
+}
+
+func runSessionsRm(cmd *cobra.Command, args []string) error {
+	store, err := loadSessionStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		if err := store.Delete(id); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted session %s\n", id)
+	}
+
+	return nil
+}
+
+func runSessionsExport(cmd *cobra.Command, args []string) error {
+	store, err := loadSessionStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	sess, err := store.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch sessionsExportFormat {
+	case "markdown", "md":
+		data = []byte(session.ExportMarkdown(sess))
+	case "json":
+		data, err = session.ExportJSON(sess)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q (use markdown or json)", sessionsExportFormat)
+	}
+
+	if sessionsExportOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(sessionsExportOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	fmt.Printf("Exported session %s to %s\n", sess.ID, sessionsExportOutput)
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// configKeyAnnotation marks flags that override a config key
+const configKeyAnnotation = "zik_config_key"
+
+var (
+	flagProfile     string
+	flagProvider    string
+	flagModel       string
+	flagTemperature float64
+	flagMaxTokens   int
+	flagThinking    bool
+	flagReasoning   bool
+)
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&flagProfile, "profile", "", "Use a named config profile (also ZIK_PROFILE)")
+	flags.StringVar(&flagProvider, "provider", "", "Use a configured provider (default: the hosted ZIK API)")
+	flags.StringVar(&flagModel, "model", "", "Override the AI model")
+	flags.Float64Var(&flagTemperature, "temperature", 0, "Override the sampling temperature (0-2)")
+	flags.IntVar(&flagMaxTokens, "max-tokens", 0, "Override the maximum tokens per reply")
+	flags.BoolVar(&flagThinking, "thinking", false, "Ask the model to reason before answering")
+	flags.BoolVar(&flagReasoning, "show-reasoning", false, "Print the model's reasoning before the answer")
+
+	bindConfigFlag(flags, "profile", "profile")
+	bindConfigFlag(flags, "provider", "provider")
+	bindConfigFlag(flags, "model", "model")
+	bindConfigFlag(flags, "temperature", "temperature")
+	bindConfigFlag(flags, "max-tokens", "max_tokens")
+	bindConfigFlag(flags, "thinking", "thinking")
+	bindConfigFlag(flags, "show-reasoning", "show_reasoning")
+}
+
+// bindConfigFlag makes flag override the dotted config key when it is set
+func bindConfigFlag(flags *pflag.FlagSet, flag, key string) {
+	if err := flags.SetAnnotation(flag, configKeyAnnotation, []string{key}); err != nil {
+		panic(err) // Only happens for misspelled flag names
+	}
+}
+
+// loadConfig returns the effective config for cmd, with flags and the selected profile applied
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, _, err := loadConfigWithOrigins(cmd)
+	return cfg, err
+}
+
+// loadConfigWithOrigins is loadConfig that also reports where each value came from
+func loadConfigWithOrigins(cmd *cobra.Command) (*config.Config, config.Origins, error) {
+	cfg, origins, err := config.LoadWithOrigins()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Visit only walks flags the user actually set
+	var flagErr error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		keys := f.Annotations[configKeyAnnotation]
+		if flagErr != nil || len(keys) == 0 {
+			return
+		}
+		if err := cfg.Set(keys[0], f.Value.String()); err != nil {
+			flagErr = fmt.Errorf("invalid --%s: %w", f.Name, err)
+			return
+		}
+		origins[keys[0]] = config.Origin{Layer: config.LayerFlag, Source: "--" + f.Name}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	// The profile is applied last so that --profile can select it
+	if err := cfg.ApplyProfile(origins); err != nil {
+		return nil, nil, err
+	}
+	if err := cfg.ApplyProvider(origins); err != nil {
+		return nil, nil, err
+	}
+
+	// Environment variables and profiles bypass the checks done by Set
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, origins, nil
+}
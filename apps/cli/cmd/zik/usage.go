@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+	"github.com/zarazaex69/zik/apps/cli/internal/render"
+	"github.com/zarazaex69/zik/apps/cli/internal/usage"
+)
+
+var (
+	flagUsage  bool
+	usageSince string
+	usageBy    string
+
+	usageCmd = &cobra.Command{
+		Use:   "usage",
+		Short: "Summarize token usage",
+		Long: `Summarize the tokens used by AI requests, grouped by command or model.
+Every request whose usage the server reports is recorded in ~/.config/zik/usage.jsonl.`,
+		Example: `  zik usage                       # Last 30 days by command
+  zik usage --since 7d --by model
+  zik usage --since 2026-01-01`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUsage,
+	}
+
+	// tracker records the usage of the running command
+	tracker = &usageTracker{}
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&flagUsage, "usage", false, "Print the tokens used when the command finishes")
+
+	usageCmd.Flags().StringVar(&usageSince, "since", "30d", "Start of the period: days, weeks, a duration or a date (7d, 2w, 12h, 2026-01-01)")
+	usageCmd.Flags().StringVar(&usageBy, "by", "command", "Group by command or model")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	since, err := usage.ParseSince(usageSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	path, err := usage.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("failed to locate usage ledger: %w", err)
+	}
+	records, err := usage.NewLedger(path).Since(since)
+	if err != nil {
+		return err
+	}
+
+	totals, err := usage.Summarize(records, usageBy)
+	if err != nil {
+		return err
+	}
+
+	period := since.Local().Format("2006-01-02 15:04")
+	if len(totals) == 0 {
+		fmt.Printf("No usage recorded since %s.\n", period)
+		return nil
+	}
+
+	fmt.Printf("Usage since %s by %s\n\n", period, usageBy)
+
+	var sum usage.Total
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tREQUESTS\tPROMPT\tCOMPLETION\tTOTAL\n", strings.ToUpper(usageBy))
+	for _, total := range totals {
+		printTotal(w, total.Key, total)
+		sum.Requests += total.Requests
+		sum.PromptTokens += total.PromptTokens
+		sum.CompletionTokens += total.CompletionTokens
+		sum.TotalTokens += total.TotalTokens
+	}
+	if len(totals) > 1 {
+		printTotal(w, "all", sum)
+	}
+	return w.Flush()
+}
+
+// printTotal writes a row of the usage table
+func printTotal(w *tabwriter.Writer, key string, total usage.Total) {
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", key, total.Requests,
+		formatTokens(total.PromptTokens), formatTokens(total.CompletionTokens), formatTokens(total.TotalTokens))
+}
+
+// newAIClient creates an AI client whose usage is recorded by the tracker
+func newAIClient(cfg *config.Config) *ai.Client {
+	client := ai.NewClient(cfg)
+	client.OnUsage(tracker.record)
+	return client
+}
+
+// usageTracker adds the usage of each request to the ledger and to a total for --usage
+type usageTracker struct {
+	command string // Command path without "zik", e.g. "code review"
+	total   usage.Total
+	ledger  *usage.Ledger
+	failed  bool // Writing the ledger failed; it is not retried
+}
+
+// start names the command whose requests are recorded
+func (t *usageTracker) start(cmd *cobra.Command) {
+	t.command = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// record notes the usage of a request
+// A ledger that cannot be written is reported once and never fails the command
+func (t *usageTracker) record(model string, u ai.Usage) {
+	t.total.Add(u)
+	if t.failed {
+		return
+	}
+
+	if t.ledger == nil {
+		path, err := usage.DefaultPath()
+		if err != nil {
+			t.fail(err)
+			return
+		}
+		t.ledger = usage.NewLedger(path)
+	}
+
+	record := usage.Record{Time: time.Now().UTC(), Command: t.command, Model: model, Usage: u}
+	if err := t.ledger.Append(record); err != nil {
+		t.fail(err)
+	}
+}
+
+// fail warns that usage is no longer recorded
+func (t *usageTracker) fail(err error) {
+	t.failed = true
+	fmt.Fprintf(os.Stderr, "Warning: usage is not recorded: %v\n", err)
+}
+
+// print reports the tokens used by the command on stderr
+func (t *usageTracker) print() {
+	if t.total.Requests == 0 {
+		fmt.Fprintln(os.Stderr, render.Dim("Tokens: no usage reported"))
+		return
+	}
+
+	requests := "1 request"
+	if t.total.Requests > 1 {
+		requests = fmt.Sprintf("%d requests", t.total.Requests)
+	}
+	fmt.Fprintln(os.Stderr, render.Dim(fmt.Sprintf("Tokens: %s prompt + %s completion = %s total (%s)",
+		formatTokens(t.total.PromptTokens), formatTokens(t.total.CompletionTokens), formatTokens(t.total.TotalTokens), requests)))
+}
+
+// formatTokens formats a token count with thousands separators
+func formatTokens(n int) string {
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
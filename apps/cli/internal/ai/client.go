@@ -1,50 +1,83 @@
 package ai
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/zarazaex69/zik/apps/cli/internal/config"
 )
 
-// Client represents an OpenAI-compatible API client
+const (
+	// maxRetries is how many times rate limited and unavailable responses are retried
+	maxRetries = 4
+
+	// retryBaseDelay is the first backoff delay, doubled on every retry
+	retryBaseDelay = time.Second
+
+	// maxRetryDelay caps the backoff; a longer Retry-After fails the request instead
+	maxRetryDelay = time.Minute
+)
+
+// Client sends chat requests to the provider selected in the config
 type Client struct {
 	httpClient *http.Client
-	endpoint   string
+	provider   Provider
 	model      string
+	timeout    time.Duration // Bounds non-streaming requests, 0 disables
+	thinking   bool          // Ask the model to reason before answering
+	retryDelay time.Duration // First backoff delay
+	retryLog   io.Writer     // Receives a line per retry
+	onUsage    func(model string, usage Usage)
 }
 
-// NewClient creates a new AI client with hardcoded endpoint
+// NewClient creates a new AI client for the active provider, the hosted API by default
 func NewClient(cfg *config.Config) *Client {
+	// The provider was checked when the config was loaded
+	provider, _ := cfg.ActiveProvider()
+
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Only for non-streaming requests
-		},
-		// Endpoint is hardcoded but can be overridden via env var for development
-		endpoint: config.GetAPIEndpoint(),
-		model:    cfg.Model,
+		httpClient: &http.Client{}, // Deadlines come from the request context
+		provider:   NewProvider(provider),
+		model:      cfg.Model,
+		timeout:    cfg.RequestTimeout,
+		thinking:   cfg.Thinking,
+		retryDelay: retryBaseDelay,
+		retryLog:   os.Stderr,
 	}
 }
 
+// OnUsage sets a function called with the token usage the server reports for each request
+func (c *Client) OnUsage(fn func(model string, usage Usage)) {
+	c.onUsage = fn
+}
+
 // ChatRequest represents a chat completion request
 type ChatRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Stream      bool      `json:"stream"`
-	Temperature float64   `json:"temperature,omitempty"`
-	MaxTokens   int       `json:"max_tokens,omitempty"`
-	Thinking    bool      `json:"thinking,omitempty"`
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	Stream        bool           `json:"stream"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+	Temperature   float64        `json:"temperature,omitempty"`
+	MaxTokens     int            `json:"max_tokens,omitempty"`
+	Thinking      bool           `json:"thinking,omitempty"`
+}
+
+// StreamOptions configures a streaming request
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Ends the stream with a usage report
 }
 
 // Message represents a chat message
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	Reasoning string `json:"reasoning_content,omitempty"` // Only set in responses
 }
 
 // ChatResponse represents a chat completion response
@@ -67,8 +100,9 @@ type Choice struct {
 
 // MessageDelta represents a streaming message delta
 type MessageDelta struct {
-	Role    string `json:"role,omitempty"`
-	Content string `json:"content,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Reasoning string `json:"reasoning_content,omitempty"`
 }
 
 // Usage represents token usage statistics
@@ -79,44 +113,45 @@ type Usage struct {
 }
 
 // Chat sends a non-streaming chat completion request
+// It is bounded by ctx and by the configured request timeout, which also covers retries
 func (c *Client) Chat(ctx context.Context, messages []Message, temperature float64, maxTokens int) (*ChatResponse, error) {
+	parent := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
 	req := ChatRequest{
 		Model:       c.model,
 		Messages:    messages,
 		Stream:      false,
 		Temperature: temperature,
 		MaxTokens:   maxTokens,
+		Thinking:    c.thinking,
 	}
 
-	body, err := json.Marshal(req)
+	resp, err := c.post(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, c.timeoutError(parent, err)
 	}
+	defer resp.Body.Close()
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/v1/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(httpReq)
+	chatResp, err := c.provider.DecodeResponse(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, c.timeoutError(parent, err)
 	}
-	defer resp.Body.Close()
+	c.recordUsage(&chatResp.Usage)
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-	}
+	return chatResp, nil
+}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// timeoutError names the request timeout when it, and not parent, ended the request
+func (c *Client) timeoutError(parent context.Context, err error) error {
+	if c.timeout > 0 && errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("no reply within request_timeout (%s): %w", c.timeout, err)
 	}
-
-	return &chatResp, nil
+	return err
 }
 
 // ChatStream sends a streaming chat completion request
@@ -129,56 +164,100 @@ func (c *Client) ChatStream(ctx context.Context, messages []Message, temperature
 		defer close(errChan)
 
 		req := ChatRequest{
-			Model:       c.model,
-			Messages:    messages,
-			Stream:      true,
-			Temperature: temperature,
-			MaxTokens:   maxTokens,
+			Model:         c.model,
+			Messages:      messages,
+			Stream:        true,
+			StreamOptions: &StreamOptions{IncludeUsage: true},
+			Temperature:   temperature,
+			MaxTokens:     maxTokens,
+			Thinking:      c.thinking,
 		}
 
-		body, err := json.Marshal(req)
+		resp, err := c.post(ctx, req)
 		if err != nil {
-			errChan <- fmt.Errorf("failed to marshal request: %w", err)
+			errChan <- err
 			return
 		}
+		defer resp.Body.Close()
 
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/v1/chat/completions", bytes.NewReader(body))
+		usage, err := c.provider.ParseStream(resp.Body, chunkChan)
+		c.recordUsage(usage)
 		if err != nil {
-			errChan <- fmt.Errorf("failed to create request: %w", err)
-			return
+			errChan <- err
 		}
+	}()
+
+	return chunkChan, errChan
+}
 
-		httpReq.Header.Set("Content-Type", "application/json")
+// StreamChunk represents a single chunk from the streaming response
+type StreamChunk struct {
+	Content      string
+	Reasoning    string // Reasoning that precedes the answer, when the model emits it
+	FinishReason string
+	Done         bool
+}
 
-		// Use a client without timeout for streaming
-		streamClient := &http.Client{
-			Timeout: 0, // No timeout for streaming
+// recordUsage passes the usage of a request to the OnUsage function
+// Nothing is recorded when the server did not report any
+func (c *Client) recordUsage(usage *Usage) {
+	if c.onUsage == nil || usage == nil || *usage == (Usage{}) {
+		return
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	c.onUsage(c.model, *usage)
+}
+
+// post sends a chat request and returns the successful response
+// Rate limited and unavailable responses are retried with exponential backoff and jitter,
+// waiting at least as long as Retry-After asks; other failures are returned as *APIError
+func (c *Client) post(ctx context.Context, req ChatRequest) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		httpReq, err := c.provider.NewRequest(ctx, req)
+		if err != nil {
+			return nil, err
 		}
-		resp, err := streamClient.Do(httpReq)
+
+		resp, err := c.httpClient.Do(httpReq)
 		if err != nil {
-			errChan <- fmt.Errorf("request failed: %w", err)
-			return
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			errChan <- fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-			return
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		apiErr := parseAPIError(resp.StatusCode, resp.Header, bodyBytes)
+
+		delay := c.backoff(attempt, apiErr.RetryAfter)
+		if !apiErr.Temporary() || attempt == maxRetries || apiErr.RetryAfter > maxRetryDelay {
+			return nil, apiErr
 		}
 
-		// Parse SSE stream
-		if err := parseSSEStream(resp.Body, chunkChan); err != nil {
-			errChan <- err
+		fmt.Fprintf(c.retryLog, "%s, retrying in %s (%d/%d)...\n", retryReason(apiErr), delay.Round(100*time.Millisecond), attempt+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
 		}
-	}()
+	}
+}
 
-	return chunkChan, errChan
+// backoff returns the delay before retry number attempt+1: the doubled base delay with
+// up to 50% random jitter, capped at maxRetryDelay, and never shorter than retryAfter
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := min(c.retryDelay<<attempt, maxRetryDelay)
+	delay = delay/2 + rand.N(delay/2+1)
+	return max(delay, retryAfter)
 }
 
-// StreamChunk represents a single chunk from the streaming response
-type StreamChunk struct {
-	Content      string
-	FinishReason string
-	Done         bool
+// retryReason describes a temporary failure in a retry notice
+func retryReason(err *APIError) string {
+	if err.Status == http.StatusTooManyRequests {
+		return "Rate limited"
+	}
+	return fmt.Sprintf("Service unavailable (status %d)", err.Status)
 }
@@ -0,0 +1,256 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// newTestClient returns a client for server with millisecond backoff
+func newTestClient(server *httptest.Server) *Client {
+	return &Client{
+		httpClient: server.Client(),
+		provider:   &openAIProvider{endpoint: server.URL},
+		model:      "test",
+		retryDelay: time.Millisecond,
+		retryLog:   io.Discard,
+	}
+}
+
+func TestChat_RetriesTemporaryErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"Rate limit exceeded.","type":"rate_limit_error","code":429}}`))
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(server).Chat(context.Background(), nil, 0.5, 10)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if attempts != 3 || resp.Choices[0].Message.Content != "ok" {
+		t.Errorf("Chat() = %+v after %d attempts, want ok after 3", resp, attempts)
+	}
+}
+
+func TestChat_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.timeout = 20 * time.Millisecond
+
+	_, err := client.Chat(context.Background(), nil, 0.5, 10)
+	close(release)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Chat() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNewClient_NoFixedTimeout(t *testing.T) {
+	cfg := config.Default()
+	cfg.RequestTimeout = 10 * time.Minute
+
+	client := NewClient(cfg)
+	if client.httpClient.Timeout != 0 || client.timeout != cfg.RequestTimeout {
+		t.Errorf("NewClient() http timeout = %s, request timeout = %s, want 0 and %s", client.httpClient.Timeout, client.timeout, cfg.RequestTimeout)
+	}
+}
+
+func TestChat_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		kind     error
+		attempts int
+		message  string
+	}{
+		{
+			name:     "rate limit gives up after retries",
+			status:   http.StatusTooManyRequests,
+			body:     `{"error":{"message":"Rate limit exceeded. Maximum 30 requests per minute.","type":"rate_limit_error","code":429}}`,
+			kind:     ErrRateLimited,
+			attempts: maxRetries + 1,
+			message:  "rate limit reached: Rate limit exceeded. Maximum 30 requests per minute.",
+		},
+		{
+			name:     "validation",
+			status:   http.StatusBadRequest,
+			body:     `{"code":400,"message":"messages is required","type":"validation_error"}`,
+			kind:     ErrValidation,
+			attempts: 1,
+			message:  "the request was rejected (status 400): messages is required",
+		},
+		{
+			name:     "auth",
+			status:   http.StatusUnauthorized,
+			body:     `{"error":"invalid token"}`,
+			kind:     ErrAuth,
+			attempts: 1,
+			message:  "authentication failed (status 401): invalid token",
+		},
+		{
+			name:     "upstream without JSON",
+			status:   http.StatusInternalServerError,
+			body:     "<html>oops</html>",
+			kind:     ErrUpstream,
+			attempts: 1,
+			message:  "the AI service is unavailable (status 500): <html>oops</html>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := newTestClient(server).Chat(context.Background(), nil, 0.5, 10)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || !errors.Is(err, tt.kind) {
+				t.Fatalf("Chat() error = %v, want %v", err, tt.kind)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.message)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("server saw %d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestChatStream_HonoursRetryAfter(t *testing.T) {
+	attempts := 0
+	var first time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if wait := time.Since(first); wait < time.Second {
+			t.Errorf("retried after %s, want at least the 1s of Retry-After", wait)
+		}
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	chunks, errs := newTestClient(server).ChatStream(context.Background(), nil, 0.5, 10)
+	var content strings.Builder
+	for chunk := range chunks {
+		content.WriteString(chunk.Content)
+	}
+	if err := <-errs; err != nil || content.String() != "hi" {
+		t.Errorf("ChatStream() = %q, %v", content.String(), err)
+	}
+}
+
+func TestClient_OnUsage(t *testing.T) {
+	var streamOptions []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		streamOptions = append(streamOptions, req["stream_options"])
+
+		if req["stream"] == true {
+			w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"hi\"}}]}\n\n" +
+				"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2,\"total_tokens\":9}}\n\ndata: [DONE]\n\n"))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"hi"}}],"usage":{"prompt_tokens":5,"completion_tokens":1}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	var got []Usage
+	client.OnUsage(func(model string, usage Usage) {
+		if model != "test" {
+			t.Errorf("OnUsage() model = %q, want test", model)
+		}
+		got = append(got, usage)
+	})
+
+	if _, err := client.Chat(context.Background(), nil, 0.5, 10); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	chunks, errs := client.ChatStream(context.Background(), nil, 0.5, 10)
+	for range chunks {
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+
+	want := []Usage{{5, 1, 6}, {7, 2, 9}}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("OnUsage() got %+v, want %+v", got, want)
+	}
+	if streamOptions[0] != nil || fmt.Sprint(streamOptions[1]) != "map[include_usage:true]" {
+		t.Errorf("stream_options = %v, want only the streaming request to include usage", streamOptions)
+	}
+}
+
+func TestChat_SendsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("request to %s with Authorization %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.provider = NewProvider(config.ProviderConfig{Protocol: config.ProtocolOpenAI, Endpoint: server.URL, APIKey: "secret"})
+	if _, err := client.Chat(context.Background(), nil, 0.5, 10); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"Wed, 01 May 2024 12:00:45 GMT", 45 * time.Second},
+		{"Wed, 01 May 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRateLimited indicates that the server's rate limit was exceeded
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrAuth indicates that the request was not authorized
+	ErrAuth = errors.New("authentication failed")
+
+	// ErrUpstream indicates a failure of the server or the model provider behind it
+	ErrUpstream = errors.New("upstream error")
+
+	// ErrValidation indicates that the server rejected the request as invalid
+	ErrValidation = errors.New("invalid request")
+)
+
+// maxErrorText is the length raw error bodies are cut to when they are not JSON
+const maxErrorText = 200
+
+// APIError is a non-200 response from the API
+// errors.Is matches it against ErrRateLimited, ErrAuth, ErrUpstream and ErrValidation
+type APIError struct {
+	Status     int
+	Type       string        // Error type reported by the server, e.g. "rate_limit_error"
+	Message    string        // Message reported by the server
+	RetryAfter time.Duration // Wait requested with Retry-After, 0 when absent
+	kind       error
+}
+
+// Error returns a message meant for users rather than the raw response
+func (e *APIError) Error() string {
+	switch e.kind {
+	case ErrRateLimited:
+		msg := "rate limit reached"
+		if e.Message != "" {
+			msg += ": " + e.Message
+		}
+		if e.RetryAfter > 0 {
+			msg += fmt.Sprintf(" (try again in %s)", e.RetryAfter.Round(time.Second))
+		}
+		return msg
+	case ErrAuth:
+		return fmt.Sprintf("authentication failed (status %d): %s", e.Status, e.Message)
+	case ErrUpstream:
+		return fmt.Sprintf("the AI service is unavailable (status %d): %s", e.Status, e.Message)
+	case ErrValidation:
+		return fmt.Sprintf("the request was rejected (status %d): %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.Status, e.Message)
+}
+
+// Unwrap returns the kind of the error
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// Temporary reports whether the request may succeed when retried
+func (e *APIError) Temporary() bool {
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// parseAPIError builds an APIError from a response status, headers and body
+// Both {"error": {"message", "type"}} and {"message", "type"} bodies are understood
+func parseAPIError(status int, header http.Header, body []byte) *APIError {
+	e := &APIError{Status: status, RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now())}
+
+	type payload struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	}
+	var wrapped struct {
+		Error json.RawMessage `json:"error"`
+		payload
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil {
+		var inner payload
+		switch {
+		case json.Unmarshal(wrapped.Error, &inner) == nil && inner.Message != "":
+			e.Message, e.Type = inner.Message, inner.Type
+		case json.Unmarshal(wrapped.Error, &e.Message) == nil && e.Message != "":
+			e.Type = wrapped.Type
+		default:
+			e.Message, e.Type = wrapped.Message, wrapped.Type
+		}
+	}
+	if e.Message == "" {
+		e.Message = strings.TrimSpace(string(body))
+		if len(e.Message) > maxErrorText {
+			e.Message = e.Message[:maxErrorText] + "..."
+		}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(status)
+	}
+
+	e.kind = errorKind(status, e.Type)
+	return e
+}
+
+// errorKind classifies an error by the type the server reported, or by status
+func errorKind(status int, typ string) error {
+	switch typ {
+	case "rate_limit_error":
+		return ErrRateLimited
+	case "authentication_error", "permission_error":
+		return ErrAuth
+	case "upstream_error":
+		return ErrUpstream
+	case "validation_error", "invalid_request_error":
+		return ErrValidation
+	}
+
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status >= 500:
+		return ErrUpstream
+	case status == http.StatusBadRequest || status == http.StatusRequestEntityTooLarge || status == http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ollamaProvider speaks the Ollama /api/chat protocol
+type ollamaProvider struct {
+	endpoint string
+	apiKey   string
+}
+
+// ollamaRequest is the body of an /api/chat request
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Think    bool            `json:"think,omitempty"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+// ollamaOptions holds the sampling settings of a request
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	NumPredict  int     `json:"num_predict,omitempty"` // Maximum tokens to generate
+}
+
+// ollamaMessage is a chat message; Thinking is only set in responses
+type ollamaMessage struct {
+	Role     string `json:"role"`
+	Content  string `json:"content"`
+	Thinking string `json:"thinking,omitempty"`
+}
+
+// ollamaResponse is a complete response, or one line of a streamed one
+// Token counts and the done reason are only set once done is true
+type ollamaResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+// usage converts the token counts of a response
+func (r *ollamaResponse) usage() *Usage {
+	return &Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// NewRequest translates req to /api/chat
+func (p *ollamaProvider) NewRequest(ctx context.Context, req ChatRequest) (*http.Request, error) {
+	messages := make([]ollamaMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = ollamaMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	body := ollamaRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   req.Stream,
+		Think:    req.Thinking,
+		Options:  ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens},
+	}
+	return newJSONRequest(ctx, p.endpoint+"/api/chat", body, p.apiKey)
+}
+
+// DecodeResponse reads a complete /api/chat response
+func (p *ollamaProvider) DecodeResponse(body io.Reader) (*ChatResponse, error) {
+	var resp ollamaResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("ollama error: %s", resp.Error)
+	}
+
+	return &ChatResponse{
+		Model: resp.Model,
+		Choices: []Choice{{
+			Message:      Message{Role: resp.Message.Role, Content: resp.Message.Content, Reasoning: resp.Message.Thinking},
+			FinishReason: resp.DoneReason,
+		}},
+		Usage: *resp.usage(),
+	}, nil
+}
+
+// ParseStream reads a streamed /api/chat response, one JSON object per line
+func (p *ollamaProvider) ParseStream(body io.Reader, chunks chan<- StreamChunk) (*Usage, error) {
+	scanner := bufio.NewScanner(body)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var resp ollamaResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			// Skip malformed lines
+			continue
+		}
+		if resp.Error != "" {
+			return nil, fmt.Errorf("ollama error: %s", resp.Error)
+		}
+
+		chunks <- StreamChunk{
+			Content:      resp.Message.Content,
+			Reasoning:    resp.Message.Thinking,
+			FinishReason: resp.DoneReason,
+			Done:         resp.Done,
+		}
+		if resp.Done {
+			return resp.usage(), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("stream reading error: %w", err)
+	}
+
+	return nil, nil
+}
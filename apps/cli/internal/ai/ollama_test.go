@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// newOllamaTestClient returns a client talking to server over the Ollama protocol
+func newOllamaTestClient(server *httptest.Server) *Client {
+	return &Client{
+		httpClient: server.Client(),
+		provider:   NewProvider(config.ProviderConfig{Protocol: config.ProtocolOllama, Endpoint: server.URL + "/"}),
+		model:      "llama3",
+		thinking:   true,
+		retryDelay: time.Millisecond,
+		retryLog:   io.Discard,
+	}
+}
+
+func TestOllama_Chat(t *testing.T) {
+	var got ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("request path = %q, want /api/chat", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none without an API key", auth)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"model":"llama3","message":{"role":"assistant","content":"Hi","thinking":"Greet back"},` +
+			`"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":3}`))
+	}))
+	defer server.Close()
+
+	messages := []Message{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "Hello"}}
+	resp, err := newOllamaTestClient(server).Chat(context.Background(), messages, 0.3, 100)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	want := ollamaRequest{
+		Model:    "llama3",
+		Messages: []ollamaMessage{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "Hello"}},
+		Think:    true,
+		Options:  ollamaOptions{Temperature: 0.3, NumPredict: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+
+	msg := resp.Choices[0].Message
+	if msg.Content != "Hi" || msg.Reasoning != "Greet back" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("Chat() message = %+v, finish reason %q", msg, resp.Choices[0].FinishReason)
+	}
+	if resp.Usage != (Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}) {
+		t.Errorf("Chat() usage = %+v", resp.Usage)
+	}
+}
+
+func TestOllama_ChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"role":"assistant","content":"","thinking":"Hmm"},"done":false}
+{"message":{"role":"assistant","content":"Hel"},"done":false}
+{"message":{"role":"assistant","content":"lo"},"done":false}
+{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":7,"eval_count":2}
+`))
+	}))
+	defer server.Close()
+
+	client := newOllamaTestClient(server)
+	var usage Usage
+	client.OnUsage(func(model string, u Usage) { usage = u })
+
+	chunks, errs := client.ChatStream(context.Background(), nil, 0.5, 10)
+	var content, reasoning strings.Builder
+	var last StreamChunk
+	for chunk := range chunks {
+		content.WriteString(chunk.Content)
+		reasoning.WriteString(chunk.Reasoning)
+		last = chunk
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+
+	if content.String() != "Hello" || reasoning.String() != "Hmm" {
+		t.Errorf("ChatStream() content = %q, reasoning = %q", content.String(), reasoning.String())
+	}
+	if !last.Done || last.FinishReason != "stop" {
+		t.Errorf("last chunk = %+v, want done with reason stop", last)
+	}
+	if usage != (Usage{PromptTokens: 7, CompletionTokens: 2, TotalTokens: 9}) {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestOllama_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Stream {
+			w.Write([]byte(`{"message":{"role":"assistant","content":"Hel"},"done":false}` + "\n" + `{"error":"model runner crashed"}` + "\n"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model 'llama3' not found"}`))
+	}))
+	defer server.Close()
+
+	client := newOllamaTestClient(server)
+	if _, err := client.Chat(context.Background(), nil, 0.5, 10); err == nil || !strings.Contains(err.Error(), "model 'llama3' not found") {
+		t.Errorf("Chat() error = %v, want the Ollama message", err)
+	}
+
+	chunks, errs := client.ChatStream(context.Background(), nil, 0.5, 10)
+	for range chunks {
+	}
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "model runner crashed") {
+		t.Errorf("ChatStream() error = %v, want the error line", err)
+	}
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// Provider translates chat requests and responses to the protocol of a backend
+type Provider interface {
+	// NewRequest builds the HTTP request for req; it is called again for every retry
+	NewRequest(ctx context.Context, req ChatRequest) (*http.Request, error)
+
+	// DecodeResponse reads the body of a successful non-streaming response
+	DecodeResponse(body io.Reader) (*ChatResponse, error)
+
+	// ParseStream reads the body of a successful streaming response, sending chunks as they arrive
+	// It returns the token usage when the backend reports it, or nil
+	ParseStream(body io.Reader, chunks chan<- StreamChunk) (*Usage, error)
+}
+
+// NewProvider returns the provider speaking the protocol of a configured backend
+func NewProvider(cfg config.ProviderConfig) Provider {
+	if cfg.Protocol == config.ProtocolOllama {
+		return &ollamaProvider{endpoint: strings.TrimSuffix(cfg.Endpoint, "/"), apiKey: cfg.Key()}
+	}
+	return &openAIProvider{endpoint: strings.TrimSuffix(cfg.Endpoint, "/"), apiKey: cfg.Key()}
+}
+
+// newJSONRequest creates a POST request with body encoded as JSON, authorized with apiKey if set
+func newJSONRequest(ctx context.Context, url string, body any, apiKey string) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+// openAIProvider speaks the OpenAI chat completions protocol used by the ZIK API and zik-server
+type openAIProvider struct {
+	endpoint string
+	apiKey   string
+}
+
+// NewRequest posts req as is to /v1/chat/completions
+func (p *openAIProvider) NewRequest(ctx context.Context, req ChatRequest) (*http.Request, error) {
+	return newJSONRequest(ctx, p.endpoint+"/v1/chat/completions", req, p.apiKey)
+}
+
+// DecodeResponse reads a chat completion
+func (p *openAIProvider) DecodeResponse(body io.Reader) (*ChatResponse, error) {
+	var chatResp ChatResponse
+	if err := json.NewDecoder(body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &chatResp, nil
+}
+
+// ParseStream reads Server-Sent Events
+func (p *openAIProvider) ParseStream(body io.Reader, chunks chan<- StreamChunk) (*Usage, error) {
+	return parseSSEStream(body, chunks)
+}
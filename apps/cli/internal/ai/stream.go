@@ -9,8 +9,10 @@ import (
 )
 
 // parseSSEStream parses Server-Sent Events stream and sends chunks to the channel
-func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) error {
+// It returns the token usage when the stream reports it, or nil
+func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) (*Usage, error) {
 	scanner := bufio.NewScanner(reader)
+	var usage *Usage
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -23,7 +25,7 @@ func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) error {
 		// Check for end of stream
 		if line == "data: [DONE]" {
 			chunkChan <- StreamChunk{Done: true}
-			return nil
+			return usage, nil
 		}
 
 		// Parse data line
@@ -33,10 +35,12 @@ func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) error {
 			var chunk struct {
 				Choices []struct {
 					Delta struct {
-						Content string `json:"content"`
+						Content   string `json:"content"`
+						Reasoning string `json:"reasoning_content"`
 					} `json:"delta"`
 					FinishReason string `json:"finish_reason"`
 				} `json:"choices"`
+				Usage *Usage `json:"usage"`
 			}
 
 			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
@@ -44,10 +48,16 @@ func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) error {
 				continue
 			}
 
+			// With include_usage the usage arrives in a final chunk without choices
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+
 			if len(chunk.Choices) > 0 {
 				choice := chunk.Choices[0]
 				chunkChan <- StreamChunk{
 					Content:      choice.Delta.Content,
+					Reasoning:    choice.Delta.Reasoning,
 					FinishReason: choice.FinishReason,
 					Done:         choice.FinishReason != "",
 				}
@@ -56,8 +66,8 @@ func parseSSEStream(reader io.Reader, chunkChan chan<- StreamChunk) error {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("stream reading error: %w", err)
+		return usage, fmt.Errorf("stream reading error: %w", err)
 	}
 
-	return nil
+	return usage, nil
 }
@@ -9,7 +9,7 @@ func TestParseSSEStream_EmptyStream(t *testing.T) {
 	reader := strings.NewReader("")
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v, want nil for empty stream", err)
 	}
@@ -32,7 +32,7 @@ func TestParseSSEStream_DoneMessage(t *testing.T) {
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -61,7 +61,7 @@ func TestParseSSEStream_ValidChunk(t *testing.T) {
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -86,6 +86,59 @@ func TestParseSSEStream_ValidChunk(t *testing.T) {
 	}
 }
 
+func TestParseSSEStream_Reasoning(t *testing.T) {
+	input := `data: {"choices":[{"delta":{"reasoning_content":"Check the edge case"}}]}
+
+data: {"choices":[{"delta":{"content":"It fails on nil"}}]}
+
+`
+	reader := strings.NewReader(input)
+	chunkChan := make(chan StreamChunk, 10)
+
+	if _, err := parseSSEStream(reader, chunkChan); err != nil {
+		t.Errorf("parseSSEStream() error = %v", err)
+	}
+
+	close(chunkChan)
+
+	chunks := make([]StreamChunk, 0)
+	for chunk := range chunkChan {
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("parseSSEStream() sent %d chunks, want 2", len(chunks))
+	}
+	if chunks[0].Reasoning != "Check the edge case" || chunks[0].Content != "" {
+		t.Errorf("parseSSEStream() first chunk = %+v, want reasoning only", chunks[0])
+	}
+	if chunks[1].Content != "It fails on nil" || chunks[1].Reasoning != "" {
+		t.Errorf("parseSSEStream() second chunk = %+v, want content only", chunks[1])
+	}
+}
+
+func TestParseSSEStream_Usage(t *testing.T) {
+	input := `data: {"choices":[{"delta":{"content":"Hi"},"finish_reason":"stop"}]}
+
+data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}
+
+data: [DONE]
+
+`
+	reader := strings.NewReader(input)
+	chunkChan := make(chan StreamChunk, 10)
+
+	usage, err := parseSSEStream(reader, chunkChan)
+	if err != nil {
+		t.Errorf("parseSSEStream() error = %v", err)
+	}
+
+	want := Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}
+	if usage == nil || *usage != want {
+		t.Errorf("parseSSEStream() usage = %+v, want %+v", usage, want)
+	}
+}
+
 func TestParseSSEStream_FinishReason(t *testing.T) {
 	input := `data: {"choices":[{"delta":{"content":""},"finish_reason":"stop"}]}
 
@@ -93,7 +146,7 @@ func TestParseSSEStream_FinishReason(t *testing.T) {
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -129,7 +182,7 @@ data: {"choices":[{"delta":{"content":"!"},"finish_reason":"stop"}]}
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -170,7 +223,7 @@ data: {"choices":[{"delta":{"content":"valid"},"finish_reason":""}]}
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v, should skip malformed chunks", err)
 	}
@@ -203,7 +256,7 @@ data: [DONE]
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -228,7 +281,7 @@ func TestParseSSEStream_NoChoices(t *testing.T) {
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -255,7 +308,7 @@ data: {"choices":[{"delta":{"content":"test"},"finish_reason":""}]}
 	reader := strings.NewReader(input)
 	chunkChan := make(chan StreamChunk, 10)
 
-	err := parseSSEStream(reader, chunkChan)
+	_, err := parseSSEStream(reader, chunkChan)
 	if err != nil {
 		t.Errorf("parseSSEStream() error = %v", err)
 	}
@@ -0,0 +1,254 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
+)
+
+// Bump is a semantic version increment
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the name of the increment
+func (b Bump) String() string {
+	return [...]string{"none", "patch", "minor", "major"}[b]
+}
+
+// header is the top of a new CHANGELOG.md
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+var (
+	// versionPattern matches a semantic version with an optional "v" prefix
+	versionPattern = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+	// groupTitles names the groups of known types, in release note order
+	groupTitles = []struct{ Type, Title string }{
+		{"feat", "Features"},
+		{"fix", "Bug Fixes"},
+		{"perf", "Performance"},
+		{"refactor", "Refactoring"},
+		{"revert", "Reverts"},
+		{"docs", "Documentation"},
+		{"style", "Style"},
+		{"test", "Tests"},
+		{"build", "Build"},
+		{"ci", "CI"},
+		{"chore", "Chores"},
+	}
+)
+
+// Entry is one commit in a changelog group
+type Entry struct {
+	Scope       string
+	Description string
+	Note        string // Text of the BREAKING CHANGE footer, if any
+}
+
+// Group is the commits of one type, or the breaking changes
+type Group struct {
+	Title    string
+	Type     string // Commit type, empty for breaking and non-conventional commits
+	Breaking bool
+	Entries  []Entry
+}
+
+// Collect groups commit messages by type, breaking changes first and
+// commits that do not follow Conventional Commits last
+// Breaking commits only appear in the breaking group
+func Collect(messages []string) []Group {
+	var breaking, other []Entry
+	byType := map[string][]Entry{}
+	var unknown []string
+
+	for _, text := range messages {
+		m := commitmsg.Parse(text)
+		h, ok := commitmsg.ParseHeader(m.Subject)
+		if !ok {
+			other = append(other, Entry{Description: m.Subject})
+			continue
+		}
+
+		entry := Entry{Scope: h.Scope, Description: h.Description}
+		if m.Breaking() {
+			for _, f := range m.Footers {
+				if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+					entry.Note = f.Value
+				}
+			}
+			breaking = append(breaking, entry)
+			continue
+		}
+
+		if _, seen := byType[h.Type]; !seen && !knownType(h.Type) {
+			unknown = append(unknown, h.Type)
+		}
+		byType[h.Type] = append(byType[h.Type], entry)
+	}
+
+	var groups []Group
+	if len(breaking) > 0 {
+		groups = append(groups, Group{Title: "Breaking Changes", Breaking: true, Entries: breaking})
+	}
+	for _, g := range groupTitles {
+		if entries := byType[g.Type]; len(entries) > 0 {
+			groups = append(groups, Group{Title: g.Title, Type: g.Type, Entries: entries})
+		}
+	}
+	slices.Sort(unknown)
+	for _, typ := range unknown {
+		groups = append(groups, Group{Title: typ, Type: typ, Entries: byType[typ]})
+	}
+	if len(other) > 0 {
+		groups = append(groups, Group{Title: "Other Changes", Entries: other})
+	}
+
+	return groups
+}
+
+// knownType reports whether typ has a group title
+func knownType(typ string) bool {
+	for _, g := range groupTitles {
+		if g.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders groups as a plain list, used as input for the release notes
+func Format(groups []Group) string {
+	var b strings.Builder
+	for i, g := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(g.Title + ":\n")
+		for _, e := range g.Entries {
+			b.WriteString("- ")
+			if e.Scope != "" {
+				b.WriteString(e.Scope + ": ")
+			}
+			b.WriteString(e.Description)
+			if e.Note != "" {
+				b.WriteString(" (BREAKING CHANGE: " + e.Note + ")")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// Recommend returns the increment the groups call for: major for breaking changes,
+// minor for features and patch for anything else
+func Recommend(groups []Group) Bump {
+	bump := BumpNone
+	for _, g := range groups {
+		switch {
+		case g.Breaking:
+			return BumpMajor
+		case g.Type == "feat":
+			bump = BumpMinor
+		case bump == BumpNone:
+			bump = BumpPatch
+		}
+	}
+	return bump
+}
+
+// Next applies an increment to a version such as "v1.4.2", keeping the "v" prefix
+// Before 1.0.0 a breaking change bumps the minor version, as the public API is not yet stable
+func Next(version string, bump Bump) (string, error) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("%q is not a semantic version", version)
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+
+	if bump == BumpMajor && major == 0 {
+		bump = BumpMinor
+	}
+	switch bump {
+	case BumpMajor:
+		major, minor, patch = major+1, 0, 0
+	case BumpMinor:
+		minor, patch = minor+1, 0
+	case BumpPatch:
+		patch++
+	}
+	return fmt.Sprintf("%s%d.%d.%d", m[1], major, minor, patch), nil
+}
+
+// Section renders a Keep a Changelog release section
+// An empty version gives an "Unreleased" section without a date
+func Section(version, date, notes string) string {
+	heading := "## [Unreleased]"
+	if version != "" {
+		heading = fmt.Sprintf("## [%s] - %s", strings.TrimPrefix(version, "v"), date)
+	}
+	return heading + "\n\n" + strings.TrimSpace(notes) + "\n"
+}
+
+// Prepend inserts a section above the latest release of a changelog, below its
+// introduction and any "Unreleased" section; an empty changelog gets the standard header
+// A section with the same heading, such as an earlier "Unreleased" section, is replaced instead
+func Prepend(changelog, section string) string {
+	if strings.TrimSpace(changelog) == "" {
+		return header + "\n" + section
+	}
+
+	lines := strings.SplitAfter(changelog, "\n")
+	name := sectionName(section)
+	start, offset := -1, 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			if start >= 0 {
+				return changelog[:start] + section + "\n" + changelog[offset:]
+			}
+			if sectionName(line) == name {
+				start = offset
+			}
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		return changelog[:start] + section
+	}
+
+	offset = 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## ") && !strings.Contains(strings.ToLower(line), "unreleased") {
+			return changelog[:offset] + section + "\n" + changelog[offset:]
+		}
+		offset += len(line)
+	}
+
+	return strings.TrimRight(changelog, "\n") + "\n\n" + section
+}
+
+// sectionName returns the bracketed name of a "## [1.0.0] - 2024-01-01" heading, lowercased
+func sectionName(heading string) string {
+	fields := strings.Fields(strings.TrimPrefix(heading, "## "))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
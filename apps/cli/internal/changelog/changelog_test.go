@@ -0,0 +1,151 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollect(t *testing.T) {
+	messages := []string{
+		"feat(api): add login endpoint",
+		"fix: handle empty body",
+		"Update README",
+		"feat(api)!: drop v1 routes",
+		"refactor: split handler\n\nBREAKING CHANGE: Handler is now an interface",
+		"wip: spike",
+		"feat: add logout",
+	}
+
+	want := []Group{
+		{Title: "Breaking Changes", Breaking: true, Entries: []Entry{
+			{Scope: "api", Description: "drop v1 routes"},
+			{Description: "split handler", Note: "Handler is now an interface"},
+		}},
+		{Title: "Features", Type: "feat", Entries: []Entry{
+			{Scope: "api", Description: "add login endpoint"},
+			{Description: "add logout"},
+		}},
+		{Title: "Bug Fixes", Type: "fix", Entries: []Entry{{Description: "handle empty body"}}},
+		{Title: "wip", Type: "wip", Entries: []Entry{{Description: "spike"}}},
+		{Title: "Other Changes", Entries: []Entry{{Description: "Update README"}}},
+	}
+
+	got := Collect(messages)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Collect() = %+v, want %+v", got, want)
+	}
+	if bump := Recommend(got); bump != BumpMajor {
+		t.Errorf("Recommend() = %v, want major", bump)
+	}
+	if text := Format(got); !strings.HasPrefix(text, "Breaking Changes:\n- api: drop v1 routes\n- split handler (BREAKING CHANGE: ") {
+		t.Errorf("Format() = %q", text)
+	}
+}
+
+func TestRecommendAndNext(t *testing.T) {
+	tests := []struct {
+		messages []string
+		version  string
+		bump     Bump
+		want     string
+	}{
+		{[]string{"fix: a", "docs: b"}, "v1.4.2", BumpPatch, "v1.4.3"},
+		{[]string{"fix: a", "feat: b"}, "1.4.2", BumpMinor, "1.5.0"},
+		{[]string{"feat!: a"}, "v1.4.2", BumpMajor, "v2.0.0"},
+		{[]string{"feat!: a"}, "v0.3.1", BumpMajor, "v0.4.0"},
+		{[]string{"chore: a"}, "v2.0.0-rc.1", BumpPatch, "v2.0.1"},
+		{nil, "v1.0.0", BumpNone, "v1.0.0"},
+	}
+
+	for _, tt := range tests {
+		bump := Recommend(Collect(tt.messages))
+		if bump != tt.bump {
+			t.Errorf("Recommend(%q) = %v, want %v", tt.messages, bump, tt.bump)
+		}
+		if got, err := Next(tt.version, bump); err != nil || got != tt.want {
+			t.Errorf("Next(%q, %v) = %q, %v, want %q", tt.version, bump, got, err, tt.want)
+		}
+	}
+
+	if _, err := Next("release-7", BumpPatch); err == nil {
+		t.Error("Next() should reject a version that is not semantic")
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	section := Section("v1.1.0", "2024-05-01", "### Added\n\n- Login\n")
+	if section != "## [1.1.0] - 2024-05-01\n\n### Added\n\n- Login\n" {
+		t.Fatalf("Section() = %q", section)
+	}
+
+	tests := []struct {
+		name      string
+		changelog string
+		want      string
+	}{
+		{
+			name:      "new file",
+			changelog: "",
+			want:      header + "\n" + section,
+		},
+		{
+			name:      "above the latest release, below unreleased",
+			changelog: "# Changelog\n\nIntro.\n\n## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n\n- First\n",
+			want:      "# Changelog\n\nIntro.\n\n## [Unreleased]\n\n" + section + "\n## [1.0.0] - 2024-01-01\n\n- First\n",
+		},
+		{
+			name:      "no releases yet",
+			changelog: "# Changelog\n",
+			want:      "# Changelog\n\n" + section,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prepend(tt.changelog, section); got != tt.want {
+				t.Errorf("Prepend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepend_ReplacesSameSection(t *testing.T) {
+	existing := "# Changelog\n\n## [Unreleased]\n\n- Old\n\n## [1.0.0] - 2024-01-01\n\n- First\n"
+	unreleased := Section("", "", "### Added\n\n- Login\n")
+	release := Section("v1.1.0", "2024-05-01", "### Added\n\n- Login\n")
+
+	tests := []struct {
+		name    string
+		section string
+		want    string
+	}{
+		{
+			name:    "unreleased",
+			section: unreleased,
+			want:    "# Changelog\n\n" + unreleased + "\n## [1.0.0] - 2024-01-01\n\n- First\n",
+		},
+		{
+			name:    "release",
+			section: release,
+			want:    "# Changelog\n\n## [Unreleased]\n\n- Old\n\n" + release + "\n## [1.0.0] - 2024-01-01\n\n- First\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			once := Prepend(existing, tt.section)
+			if once != tt.want {
+				t.Errorf("Prepend() = %q, want %q", once, tt.want)
+			}
+			if twice := Prepend(once, tt.section); twice != once {
+				t.Errorf("Prepend() twice = %q, want %q", twice, once)
+			}
+		})
+	}
+
+	// The last section of the file is replaced as well
+	if got := Prepend("# Changelog\n\n## [Unreleased]\n\n- Old\n", unreleased); got != "# Changelog\n\n"+unreleased {
+		t.Errorf("Prepend() = %q", got)
+	}
+}
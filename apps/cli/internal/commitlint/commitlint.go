@@ -0,0 +1,165 @@
+package commitlint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Files lists the commitlint configuration files that are read, in lookup order
+// JavaScript configs are not evaluated; their rules are read when written as literals
+var Files = []string{
+	".commitlintrc.json",
+	".commitlintrc.yaml",
+	".commitlintrc.yml",
+	".commitlintrc",
+	"commitlint.config.js",
+	"commitlint.config.cjs",
+	"commitlint.config.mjs",
+	"commitlint.config.ts",
+}
+
+var (
+	// trailingCommaPattern matches commas before a closing bracket, allowed in JavaScript
+	trailingCommaPattern = regexp.MustCompile(`,(\s*[\]}])`)
+
+	// lineCommentPattern matches JavaScript line comments that contain no quotes, leaving URLs in strings alone
+	lineCommentPattern = regexp.MustCompile(`(?m)(^|\s)//[^'"\n]*$`)
+)
+
+// Config holds the commitlint rules zik follows
+type Config struct {
+	Path            string   // File the rules were read from
+	Types           []string // type-enum
+	Scopes          []string // scope-enum
+	HeaderMaxLength int      // header-max-length, 0 when not set
+}
+
+// Find reads the first commitlint configuration in dir
+// It returns false when there is none
+func Find(dir string) (Config, bool, error) {
+	for _, name := range Files {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Config{}, false, err
+		}
+
+		cfg, err := Parse(name, data)
+		if err != nil {
+			return Config{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		cfg.Path = path
+		return cfg, true, nil
+	}
+	return Config{}, false, nil
+}
+
+// Parse reads the rules of a commitlint configuration file named name
+func Parse(name string, data []byte) (Config, error) {
+	if strings.HasSuffix(name, "js") || strings.HasSuffix(name, ".ts") {
+		return parseScript(string(data))
+	}
+
+	// JSON is valid YAML, so one decoder handles .json, .yaml and the extensionless rc file
+	var file struct {
+		Rules map[string][]any `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Config{}, err
+	}
+	return fromRules(file.Rules), nil
+}
+
+// parseScript extracts rules written as literals from a JavaScript or TypeScript config
+func parseScript(source string) (Config, error) {
+	source = lineCommentPattern.ReplaceAllString(source, "$1")
+	rules := map[string][]any{}
+
+	for _, rule := range []string{"type-enum", "scope-enum", "header-max-length"} {
+		literal, ok := ruleLiteral(source, rule)
+		if !ok {
+			continue
+		}
+
+		// Single-quoted strings are valid YAML flow scalars; trailing commas are not
+		var value []any
+		if err := yaml.Unmarshal([]byte(trailingCommaPattern.ReplaceAllString(literal, "$1")), &value); err != nil {
+			return Config{}, fmt.Errorf("cannot read rule %s: %w", rule, err)
+		}
+		rules[rule] = value
+	}
+
+	return fromRules(rules), nil
+}
+
+// ruleLiteral returns the array literal assigned to a rule, matching nested brackets
+func ruleLiteral(source, rule string) (string, bool) {
+	pattern := regexp.MustCompile(`['"]?` + regexp.QuoteMeta(rule) + `['"]?\s*:\s*\[`)
+	loc := pattern.FindStringIndex(source)
+	if loc == nil {
+		return "", false
+	}
+
+	start := loc[1] - 1
+	depth := 0
+	for i := start; i < len(source); i++ {
+		switch source[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return source[start : i+1], true
+			}
+		}
+	}
+	return "", false
+}
+
+// fromRules converts commitlint rules of the form [level, "always", value] into a Config
+// Disabled rules (level 0) and "never" rules are ignored
+func fromRules(rules map[string][]any) Config {
+	var cfg Config
+
+	value := func(name string) (any, bool) {
+		rule := rules[name]
+		if len(rule) < 3 || fmt.Sprint(rule[0]) == "0" || rule[1] != "always" {
+			return nil, false
+		}
+		return rule[2], true
+	}
+
+	if v, ok := value("type-enum"); ok {
+		cfg.Types = stringList(v)
+	}
+	if v, ok := value("scope-enum"); ok {
+		cfg.Scopes = stringList(v)
+	}
+	if v, ok := value("header-max-length"); ok {
+		if n, ok := v.(int); ok {
+			cfg.HeaderMaxLength = n
+		}
+	}
+
+	return cfg
+}
+
+// stringList converts a decoded list into strings, skipping other values
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	var result []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package commitlint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want Config
+	}{
+		{
+			name: "json",
+			file: ".commitlintrc.json",
+			data: `{"extends": ["@commitlint/config-conventional"], "rules": {
+				"type-enum": [2, "always", ["feat", "fix", "chore"]],
+				"scope-enum": [2, "always", ["cli", "server", "web"]],
+				"header-max-length": [2, "always", 100]
+			}}`,
+			want: Config{Types: []string{"feat", "fix", "chore"}, Scopes: []string{"cli", "server", "web"}, HeaderMaxLength: 100},
+		},
+		{
+			name: "yaml",
+			file: ".commitlintrc.yaml",
+			data: "rules:\n  type-enum: [2, always, [feat, fix]]\n  scope-enum:\n    - 2\n    - always\n    - - api\n      - ui\n",
+			want: Config{Types: []string{"feat", "fix"}, Scopes: []string{"api", "ui"}},
+		},
+		{
+			name: "disabled and never rules",
+			file: ".commitlintrc",
+			data: `{"rules": {"type-enum": [0, "always", ["feat"]], "scope-enum": [2, "never", ["wip"]]}}`,
+			want: Config{},
+		},
+		{
+			name: "javascript",
+			file: "commitlint.config.js",
+			data: `// Enforced in CI
+module.exports = {
+  extends: ['@commitlint/config-conventional'],
+  rules: {
+    'type-enum': [
+      2,
+      'always',
+      ['feat', 'fix', 'docs',], // trailing commas are fine
+    ],
+    'scope-enum': [RuleConfigSeverity.Error, 'always', ["cli", "server"]],
+    "header-max-length": [2, 'always', 72],
+  },
+};
+`,
+			want: Config{Types: []string{"feat", "fix", "docs"}, Scopes: []string{"cli", "server"}, HeaderMaxLength: 72},
+		},
+		{
+			name: "javascript without literal rules",
+			file: "commitlint.config.ts",
+			data: "export default { extends: ['@commitlint/config-conventional'] };\n",
+			want: Config{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.file, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, found, err := Find(dir); found || err != nil {
+		t.Fatalf("Find() in empty dir = %v, %v", found, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "commitlint.config.js"), []byte(`module.exports = {rules: {'type-enum': [2, 'always', ['js']]}}`), 0644)
+	os.WriteFile(filepath.Join(dir, ".commitlintrc.json"), []byte(`{"rules": {"type-enum": [2, "always", ["json"]]}}`), 0644)
+
+	cfg, found, err := Find(dir)
+	if err != nil || !found {
+		t.Fatalf("Find() = %v, %v", found, err)
+	}
+	if cfg.Path != filepath.Join(dir, ".commitlintrc.json") || !reflect.DeepEqual(cfg.Types, []string{"json"}) {
+		t.Errorf("Find() = %+v, want the JSON config first", cfg)
+	}
+
+	os.WriteFile(filepath.Join(dir, ".commitlintrc.json"), []byte(`{"rules": `), 0644)
+	if _, _, err := Find(dir); err == nil {
+		t.Error("Find() should report a malformed config")
+	}
+}
@@ -0,0 +1,52 @@
+package commitmsg
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// trackerKeyPattern matches Jira-style keys such as PROJ-42
+	trackerKeyPattern = regexp.MustCompile(`(?:^|[/_-])(([A-Z][A-Z0-9]+)-[0-9]+)(?:$|[/_-])`)
+
+	// notTrackerKeys lists names of standards and versions written like tracker keys, as in UTF-8 or SHA-256
+	notTrackerKeys = []string{
+		"AES", "AGPL", "BASE", "CP", "CSS", "CVE", "ECMA", "ES", "GPL", "HTML", "HTTP", "IE", "IEEE", "ISO",
+		"LGPL", "MD", "OAUTH", "PEP", "PHP", "RFC", "RSA", "SHA", "SSL", "TLS", "UCS", "UTF", "WIN",
+	}
+
+	// workTypes lists the branch prefixes for changes that may resolve an issue
+	workTypes = `(?:feat|feature|fix|bugfix|hotfix|chore|docs|refactor|perf|test|style|build|ci)`
+
+	// issueNumberPattern matches issue numbers with an issue-like prefix (issue-123, gh-123, #123)
+	// or directly after a work type and before a slug (fix/123-crash); other numbers,
+	// as in release/2024-q1, hotfix/2023-10-login or v2-migration, are ignored
+	issueNumberPattern = regexp.MustCompile(`(?i)(?:(?:^|/)(?:issues?[-_]?|gh[-_]?|#)([0-9]+)(?:$|[/_-])|^` + workTypes + `/([0-9]+)[-_][a-z])`)
+)
+
+// IssueFooter infers an issue reference footer from a branch name
+// Tracker keys become "Refs: PROJ-42" and issue numbers become "Closes #123"
+func IssueFooter(branch string) (Footer, bool) {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "HEAD" {
+		return Footer{}, false
+	}
+
+	// Search again from the end of a skipped key, since its trailing separator may lead the next one
+	for rest := branch; ; {
+		m := trackerKeyPattern.FindStringSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		if !slices.Contains(notTrackerKeys, rest[m[4]:m[5]]) {
+			return Footer{Token: "Refs", Sep: ": ", Value: rest[m[2]:m[3]]}, true
+		}
+		rest = rest[m[3]:]
+	}
+	if m := issueNumberPattern.FindStringSubmatch(branch); m != nil {
+		return Footer{Token: "Closes", Sep: " #", Value: m[1] + m[2]}, true
+	}
+
+	return Footer{}, false
+}
@@ -0,0 +1,50 @@
+package commitmsg
+
+import "testing"
+
+func TestIssueFooter(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feat/PROJ-42-login", "Refs: PROJ-42"},
+		{"ABC-7", "Refs: ABC-7"},
+		{"fix/123-crash-on-start", "Closes #123"},
+		{"issue-77", "Closes #77"},
+		{"bugfix/gh-9", "Closes #9"},
+		{"main", ""},
+		{"HEAD", ""},
+		{"release/1.2", ""},
+		{"feat/login-v2", ""},
+		{"release/2024", ""},
+		{"release/2024-03", ""},
+		{"v2-migration", ""},
+		{"chore/python3-upgrade", ""},
+		{"hotfix/42", ""},
+		{"feat/issue-12-export", "Closes #12"},
+		{"#31-typo", "Closes #31"},
+		{"hotfix/7-login", "Closes #7"},
+		{"release/2024-q1", ""},
+		{"hotfix/2023-10-login", ""},
+		{"release/v1.2.3-rc1", ""},
+		{"2024-cleanup", ""},
+		{"feat/v2-api", ""},
+		{"fix/UTF-8-decoding", ""},
+		{"feat/SHA-256-checksums", ""},
+		{"chore/ISO-8601-dates", ""},
+		{"fix/TLS-13-PROJ-9-handshake", "Refs: PROJ-9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			footer, ok := IssueFooter(tt.branch)
+			got := ""
+			if ok {
+				got = footer.String()
+			}
+			if got != tt.want {
+				t.Errorf("IssueFooter(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// SubjectLimit is the maximum length of the subject line
+	SubjectLimit = 72
+
+	// BodyWidth is the column at which the body is wrapped
+	BodyWidth = 72
+)
+
+// footerPattern matches git trailer and Conventional Commits footer lines, e.g. "Refs: #12" or "Closes #12"
+var footerPattern = regexp.MustCompile(`^(BREAKING CHANGE|BREAKING-CHANGE|[A-Za-z][A-Za-z-]*)(: | #)(.+)$`)
+
+// Footer is a single Conventional Commits footer
+type Footer struct {
+	Token string // e.g. "BREAKING CHANGE", "Refs", "Closes"
+	Sep   string // ": " or " #"
+	Value string
+}
+
+// String formats the footer as a single line
+func (f Footer) String() string {
+	return f.Token + f.Sep + f.Value
+}
+
+// Message is a commit message split into its parts
+type Message struct {
+	Subject string
+	Body    string
+	Footers []Footer
+}
+
+// Parse splits a commit message into subject, body and trailing footers
+func Parse(text string) Message {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	subject, rest, _ := strings.Cut(text, "\n")
+	msg := Message{Subject: strings.TrimSpace(subject)}
+
+	paragraphs := splitParagraphs(rest)
+	if n := len(paragraphs); n > 0 {
+		if footers, ok := parseFooters(paragraphs[n-1]); ok {
+			msg.Footers = footers
+			paragraphs = paragraphs[:n-1]
+		}
+	}
+	msg.Body = strings.Join(paragraphs, "\n\n")
+
+	return msg
+}
+
+// splitParagraphs splits text on blank lines, dropping empty paragraphs
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	var current []string
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t"))
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, "\n"))
+	}
+
+	return paragraphs
+}
+
+// parseFooters parses a paragraph made only of footers, continuation lines included
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if m := footerPattern.FindStringSubmatch(line); m != nil {
+			footers = append(footers, Footer{Token: m[1], Sep: m[2], Value: strings.TrimSpace(m[3])})
+			continue
+		}
+		// Indented lines continue the previous footer value
+		if len(footers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			footers[len(footers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		return nil, false
+	}
+	return footers, len(footers) > 0
+}
+
+// Breaking reports whether the message declares a breaking change
+func (m Message) Breaking() bool {
+	for _, f := range m.Footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			return true
+		}
+	}
+	head, _, _ := strings.Cut(m.Subject, ":")
+	return strings.HasSuffix(head, "!")
+}
+
+// Header is the Conventional Commits header of a subject: type(scope)!: description
+type Header struct {
+	Type        string
+	Scope       string
+	Bang        bool // The subject is marked breaking with "!"
+	Description string
+}
+
+// ParseHeader splits a Conventional Commits subject, reporting false for other subjects
+func ParseHeader(subject string) (Header, bool) {
+	match := headerPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return Header{}, false
+	}
+	return Header{Type: strings.ToLower(match[1]), Scope: match[3], Bang: match[4] != "", Description: match[5]}, true
+}
+
+// HasFooter reports whether a footer with token exists, ignoring case
+func (m Message) HasFooter(token string) bool {
+	for _, f := range m.Footers {
+		if strings.EqualFold(f.Token, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFooter appends a footer unless one with the same token and value is already present
+func (m *Message) AddFooter(f Footer) {
+	for _, existing := range m.Footers {
+		if strings.EqualFold(existing.Token, f.Token) && strings.EqualFold(existing.Value, f.Value) {
+			return
+		}
+	}
+	m.Footers = append(m.Footers, f)
+}
+
+// String formats the message with the subject shortened to SubjectLimit and the body wrapped at BodyWidth
+func (m Message) String() string {
+	parts := []string{ShortenSubject(m.Subject, SubjectLimit)}
+
+	if body := strings.TrimSpace(m.Body); body != "" {
+		parts = append(parts, Wrap(body, BodyWidth))
+	}
+
+	if len(m.Footers) > 0 {
+		lines := make([]string, 0, len(m.Footers))
+		for _, f := range m.Footers {
+			lines = append(lines, wrapFooter(f.String(), BodyWidth))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// ShortenSubject cuts a subject longer than limit at the last word boundary that fits
+func ShortenSubject(subject string, limit int) string {
+	subject = strings.TrimSpace(subject)
+	runes := []rune(subject)
+	if len(runes) <= limit {
+		return subject
+	}
+
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-.")
+}
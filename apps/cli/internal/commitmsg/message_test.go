@@ -0,0 +1,139 @@
+package commitmsg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	text := `feat(auth)!: replace session cookies with JWT
+
+Sessions were stored in memory, which broke horizontal scaling.
+
+- tokens are signed with the existing secret
+
+BREAKING CHANGE: the /login endpoint now returns a token
+  instead of setting a cookie
+Refs: PROJ-42`
+
+	msg := Parse(text)
+
+	if msg.Subject != "feat(auth)!: replace session cookies with JWT" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if !strings.HasPrefix(msg.Body, "Sessions were stored") || !strings.HasSuffix(msg.Body, "existing secret") {
+		t.Errorf("Body = %q", msg.Body)
+	}
+
+	expected := []Footer{
+		{Token: "BREAKING CHANGE", Sep: ": ", Value: "the /login endpoint now returns a token instead of setting a cookie"},
+		{Token: "Refs", Sep: ": ", Value: "PROJ-42"},
+	}
+	if !reflect.DeepEqual(msg.Footers, expected) {
+		t.Errorf("Footers = %+v, want %+v", msg.Footers, expected)
+	}
+	if !msg.Breaking() {
+		t.Error("Breaking() = false, want true")
+	}
+}
+
+func TestParse_SubjectOnly(t *testing.T) {
+	msg := Parse("  fix: handle nil response\n\n")
+
+	if msg.Subject != "fix: handle nil response" || msg.Body != "" || len(msg.Footers) != 0 {
+		t.Errorf("Parse() = %+v", msg)
+	}
+	if msg.Breaking() {
+		t.Error("Breaking() = true, want false")
+	}
+}
+
+func TestParse_BodyIsNotFooter(t *testing.T) {
+	msg := Parse("docs: update readme\n\nNote: this only touches docs.\nNothing else changed.")
+
+	if len(msg.Footers) != 0 {
+		t.Errorf("Footers = %+v, want none for a prose paragraph", msg.Footers)
+	}
+	if msg.Body == "" {
+		t.Error("Body should keep the paragraph")
+	}
+}
+
+func TestAddFooter(t *testing.T) {
+	msg := Message{Subject: "fix: x"}
+	msg.AddFooter(Footer{Token: "Closes", Sep: " #", Value: "12"})
+	msg.AddFooter(Footer{Token: "closes", Sep: " #", Value: "12"})
+
+	if len(msg.Footers) != 1 {
+		t.Errorf("AddFooter() added a duplicate: %+v", msg.Footers)
+	}
+	if !msg.HasFooter("CLOSES") {
+		t.Error("HasFooter() should ignore case")
+	}
+}
+
+func TestString(t *testing.T) {
+	msg := Message{
+		Subject: "feat(cli): add a very long subject line that keeps going well past the seventy two column limit",
+		Body:    strings.Repeat("motivation ", 20),
+		Footers: []Footer{{Token: "Closes", Sep: " #", Value: "123"}},
+	}
+
+	out := msg.String()
+	lines := strings.Split(out, "\n")
+
+	for _, line := range lines {
+		if len([]rune(line)) > 72 {
+			t.Errorf("line exceeds 72 columns: %q", line)
+		}
+	}
+	if lines[1] != "" {
+		t.Error("subject must be followed by a blank line")
+	}
+	if !strings.HasSuffix(out, "\n\nCloses #123") {
+		t.Errorf("String() should end with the footer block:\n%s", out)
+	}
+
+	// The formatted message must parse back into the same parts
+	if reparsed := Parse(out); len(reparsed.Footers) != 1 || reparsed.Subject != lines[0] {
+		t.Errorf("String() output does not round-trip: %+v", reparsed)
+	}
+}
+
+func TestShortenSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		limit   int
+		want    string
+	}{
+		{"fix: short", 72, "fix: short"},
+		{"feat: add support for many things, and more", 30, "feat: add support for many"},
+		{"feat: abcdefghijklmnopqrstuvwxyz", 10, "feat"},
+	}
+
+	for _, tt := range tests {
+		if got := ShortenSubject(tt.subject, tt.limit); got != tt.want {
+			t.Errorf("ShortenSubject(%q, %d) = %q, want %q", tt.subject, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    Header
+		ok      bool
+	}{
+		{"feat(api)!: drop v1", Header{Type: "feat", Scope: "api", Bang: true, Description: "drop v1"}, true},
+		{"Fix: handle nil", Header{Type: "fix", Description: "handle nil"}, true},
+		{"Update README", Header{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseHeader(tt.subject)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseHeader(%q) = %+v, %v, want %+v, %v", tt.subject, got, ok, tt.want, tt.ok)
+		}
+	}
+}
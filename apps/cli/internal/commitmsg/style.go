@@ -0,0 +1,187 @@
+package commitmsg
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// minStyleSample is the number of commits needed before a style is inferred
+	minStyleSample = 5
+
+	// maxStyleScopes caps the scopes reported in a style
+	maxStyleScopes = 12
+
+	// maxStyleExamples caps the example messages kept in a style
+	maxStyleExamples = 5
+
+	// maxExampleLines caps the lines of each example message
+	maxExampleLines = 8
+)
+
+var (
+	// ticketPattern matches tracker keys such as PROJ-42 or issue references such as #42
+	ticketPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b|#[0-9]+\b`)
+
+	// skipPattern matches subjects that say nothing about the house style
+	skipPattern = regexp.MustCompile(`^(Merge |Revert "|fixup! |squash! |amend! |Initial commit)`)
+)
+
+// Style is the commit message style observed in a repository's history
+type Style struct {
+	Sampled      int      // Number of messages the style was learned from
+	Conventional bool     // Most subjects follow Conventional Commits
+	Scopes       []string // Scopes used more than once, most frequent first
+	Capitalized  bool     // Descriptions usually start with a capital letter
+	Tickets      bool     // Subjects usually reference a ticket or issue
+	Bodies       bool     // Most messages have a body
+	Examples     []string // Representative recent messages
+}
+
+// LearnStyle infers the house style from recent commit messages, newest first
+// The zero Style is returned when there are too few messages to tell
+func LearnStyle(messages []string) Style {
+	var sample []Message
+	for _, text := range messages {
+		if m := Parse(text); m.Subject != "" && !skipPattern.MatchString(m.Subject) {
+			sample = append(sample, m)
+		}
+	}
+	if len(sample) < minStyleSample {
+		return Style{}
+	}
+
+	style := Style{Sampled: len(sample)}
+	var conventional, capitalized, tickets, bodies int
+	scopes := map[string]int{}
+
+	for _, m := range sample {
+		description := m.Subject
+		if match := headerPattern.FindStringSubmatch(m.Subject); match != nil {
+			conventional++
+			if match[3] != "" {
+				scopes[match[3]]++
+			}
+			description = match[5]
+		}
+
+		if startsCapitalized(description) {
+			capitalized++
+		}
+		if ticketPattern.MatchString(m.Subject) {
+			tickets++
+		}
+		if m.Body != "" {
+			bodies++
+		}
+	}
+
+	style.Conventional = conventional*2 > len(sample)
+	style.Capitalized = capitalized*2 > len(sample)
+	style.Tickets = tickets*2 > len(sample)
+	style.Bodies = bodies*2 > len(sample)
+	style.Scopes = frequent(scopes, 2, maxStyleScopes)
+	style.Examples = examples(sample, style)
+
+	return style
+}
+
+// startsCapitalized reports whether text starts with a capitalized word rather than an acronym
+func startsCapitalized(text string) bool {
+	runes := []rune(strings.TrimSpace(text))
+	return len(runes) > 1 && unicode.IsUpper(runes[0]) && unicode.IsLower(runes[1])
+}
+
+// frequent returns the keys counted at least min times, most frequent first, at most limit of them
+func frequent(counts map[string]int, min, limit int) []string {
+	var keys []string
+	for key, n := range counts {
+		if n >= min {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+// examples picks recent messages that match the dominant style, preferring different types
+func examples(sample []Message, style Style) []string {
+	var picked []string
+	var later []string
+	seenTypes := map[string]bool{}
+
+	for _, m := range sample {
+		match := headerPattern.FindStringSubmatch(m.Subject)
+		if style.Conventional != (match != nil) {
+			continue
+		}
+
+		text := exampleText(m, style.Bodies)
+		if match != nil && seenTypes[match[1]] {
+			later = append(later, text)
+			continue
+		}
+		if match != nil {
+			seenTypes[match[1]] = true
+		}
+		picked = append(picked, text)
+	}
+
+	picked = append(picked, later...)
+	if len(picked) > maxStyleExamples {
+		picked = picked[:maxStyleExamples]
+	}
+	return picked
+}
+
+// exampleText formats a message as an example, keeping the body only when bodies are the norm
+func exampleText(m Message, withBody bool) string {
+	if !withBody {
+		m.Body = ""
+	}
+	lines := strings.Split(m.String(), "\n")
+	if len(lines) > maxExampleLines {
+		lines = append(lines[:maxExampleLines], "...")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Conventions describes the style as short instructions for the model
+func (s Style) Conventions() []string {
+	if s.Sampled == 0 {
+		return nil
+	}
+
+	var conventions []string
+	if s.Conventional {
+		conventions = append(conventions, "Subjects follow Conventional Commits")
+	} else {
+		conventions = append(conventions, "Subjects do not use Conventional Commits prefixes")
+	}
+	if len(s.Scopes) > 0 {
+		conventions = append(conventions, fmt.Sprintf("Common scopes: %s", strings.Join(s.Scopes, ", ")))
+	}
+	if s.Capitalized {
+		conventions = append(conventions, "Descriptions start with a capital letter")
+	} else {
+		conventions = append(conventions, "Descriptions start with a lowercase letter")
+	}
+	if s.Tickets {
+		conventions = append(conventions, "Subjects reference a ticket or issue, placed as in the examples")
+	}
+	if s.Bodies {
+		conventions = append(conventions, "Most commits have a body explaining the change")
+	} else {
+		conventions = append(conventions, "Most commits have a subject line only")
+	}
+	return conventions
+}
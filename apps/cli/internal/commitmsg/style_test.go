@@ -0,0 +1,78 @@
+package commitmsg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLearnStyle(t *testing.T) {
+	history := []string{
+		"feat(api): Add login endpoint PROJ-12\n\nAdds the endpoint used by the web client.",
+		"Merge branch 'main' into feature",
+		"fix(api): Handle expired tokens PROJ-15\n\nTokens were accepted after expiry.",
+		"feat(ui): Show login form PROJ-11\n\nThe form posts to the new endpoint.",
+		"docs: Describe login PROJ-10\n\nExplains the flow.",
+		"fix(ui): Reset form on error PROJ-9",
+		"chore(deps): Bump chi PROJ-8\n\nSecurity fix.",
+		"fixup! fix(ui): Reset form on error",
+	}
+
+	style := LearnStyle(history)
+
+	if style.Sampled != 6 {
+		t.Errorf("Sampled = %d, want 6 (merges and fixups skipped)", style.Sampled)
+	}
+	if !style.Conventional || !style.Capitalized || !style.Tickets || !style.Bodies {
+		t.Errorf("LearnStyle() = %+v, want conventional, capitalized, tickets and bodies", style)
+	}
+	if want := []string{"api", "ui"}; !reflect.DeepEqual(style.Scopes, want) {
+		t.Errorf("Scopes = %v, want %v", style.Scopes, want)
+	}
+
+	// One example per type first, then the rest, newest first
+	var subjects []string
+	for _, example := range style.Examples {
+		subject, _, _ := strings.Cut(example, "\n")
+		subjects = append(subjects, subject)
+	}
+	want := []string{
+		"feat(api): Add login endpoint PROJ-12",
+		"fix(api): Handle expired tokens PROJ-15",
+		"docs: Describe login PROJ-10",
+		"chore(deps): Bump chi PROJ-8",
+		"feat(ui): Show login form PROJ-11",
+	}
+	if !reflect.DeepEqual(subjects, want) {
+		t.Errorf("example subjects = %q, want %q", subjects, want)
+	}
+	if !strings.Contains(style.Examples[0], "Adds the endpoint") {
+		t.Errorf("examples should keep bodies when bodies are common: %q", style.Examples[0])
+	}
+
+	conventions := strings.Join(style.Conventions(), "\n")
+	for _, want := range []string{"Conventional Commits", "Common scopes: api, ui", "capital letter", "ticket", "body"} {
+		if !strings.Contains(conventions, want) {
+			t.Errorf("Conventions() missing %q:\n%s", want, conventions)
+		}
+	}
+}
+
+func TestLearnStyle_PlainHistory(t *testing.T) {
+	history := []string{"update readme", "fix typo in parser", "add cli flag", "remove dead code", "tweak ci"}
+
+	style := LearnStyle(history)
+	if style.Conventional || style.Capitalized || style.Bodies || len(style.Scopes) != 0 {
+		t.Errorf("LearnStyle() = %+v, want a plain lowercase style", style)
+	}
+	if len(style.Examples) != 5 {
+		t.Errorf("Examples = %q, want all 5 subjects", style.Examples)
+	}
+}
+
+func TestLearnStyle_TooFewCommits(t *testing.T) {
+	style := LearnStyle([]string{"feat: a", "fix: b", "Initial commit"})
+	if style.Sampled != 0 || style.Conventions() != nil {
+		t.Errorf("LearnStyle() = %+v, want zero style for a short history", style)
+	}
+}
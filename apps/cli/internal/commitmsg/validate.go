@@ -0,0 +1,207 @@
+package commitmsg
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTypes are the commit types accepted when no other list is configured
+var DefaultTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+var (
+	// headerPattern matches a Conventional Commits header: type(scope)!: description
+	headerPattern = regexp.MustCompile(`^([A-Za-z]+)(\(([^()]*)\))?(!)?: (.*)$`)
+
+	// scopePattern matches a well-formed scope such as "api", "auth/jwt" or "ui-kit"
+	scopePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+	// preamblePattern matches an introductory line some models put before the message
+	preamblePattern = regexp.MustCompile(`(?i)^(here('s| is)|sure|certainly|okay|ok)\b.*:$|^(suggested |proposed |generated )?commit message:?$`)
+
+	// breakingPattern matches a breaking change footer written in the wrong case
+	breakingPattern = regexp.MustCompile(`(?i)^breaking[ -]change:`)
+)
+
+// imperativeVerbs are common verbs recognized in their third person form, e.g. "adds"
+var imperativeVerbs = []string{
+	"add", "fix", "update", "remove", "change", "improve", "implement", "use", "make", "move",
+	"rename", "refactor", "bump", "allow", "support", "handle", "create", "delete", "introduce",
+	"ensure", "prevent", "replace", "set", "drop", "enable", "disable", "document", "extract",
+	"simplify", "clean", "correct", "adjust", "upgrade", "migrate", "split", "merge", "revert",
+	"optimize", "reduce", "increase", "show", "hide", "return", "skip", "avoid", "apply",
+}
+
+// imperativeExceptions are words whose ending looks past or progressive but that are imperative or nouns
+var imperativeExceptions = []string{"embed", "feed", "seed", "need", "speed", "shed", "proceed", "exceed", "succeed", "bring", "ping", "string", "thing", "ring", "spring"}
+
+// nonImperative reports whether word is a past, progressive or third person verb form
+func nonImperative(word string) bool {
+	word = strings.ToLower(word)
+	if len(word) < 4 || slices.Contains(imperativeExceptions, word) {
+		return false
+	}
+	if strings.HasSuffix(word, "ed") || strings.HasSuffix(word, "ing") {
+		return true
+	}
+
+	for _, stem := range []string{strings.TrimSuffix(word, "s"), strings.TrimSuffix(word, "es"), strings.TrimSuffix(word, "ies") + "y"} {
+		if stem != word && slices.Contains(imperativeVerbs, stem) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules configures Validate
+type Rules struct {
+	Types        []string // Allowed types, DefaultTypes when empty
+	Scopes       []string // Allowed scopes, any well-formed scope when empty
+	SubjectLimit int      // Maximum subject length, SubjectLimit when zero
+	Capitalized  bool     // Descriptions start with a capital letter instead of a lowercase one
+}
+
+// Validate checks a message against Conventional Commits 1.0 and returns the problems found
+func Validate(m Message, rules Rules) []string {
+	var problems []string
+
+	types := rules.Types
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	limit := rules.SubjectLimit
+	if limit == 0 {
+		limit = SubjectLimit
+	}
+
+	match := headerPattern.FindStringSubmatch(m.Subject)
+	if match == nil {
+		return append(problems, fmt.Sprintf("the subject %q does not have the form \"type(scope): description\"", m.Subject))
+	}
+	typ, parens, scope, description := match[1], match[2], match[3], match[5]
+
+	if !slices.Contains(types, typ) {
+		problems = append(problems, fmt.Sprintf("unknown type %q, use one of: %s", typ, strings.Join(types, ", ")))
+	}
+
+	switch {
+	case parens != "" && scope == "":
+		problems = append(problems, "the scope is empty, remove the parentheses or name a scope")
+	case scope != "" && !scopePattern.MatchString(scope):
+		problems = append(problems, fmt.Sprintf("invalid scope %q, use a lowercase noun such as \"api\" or \"auth/jwt\"", scope))
+	case scope != "" && len(rules.Scopes) > 0 && !slices.Contains(rules.Scopes, scope):
+		problems = append(problems, fmt.Sprintf("unknown scope %q, use one of: %s", scope, strings.Join(rules.Scopes, ", ")))
+	}
+
+	if n := utf8.RuneCountInString(m.Subject); n > limit {
+		problems = append(problems, fmt.Sprintf("the subject is %d characters long, keep it within %d", n, limit))
+	}
+
+	problems = append(problems, descriptionProblems(description, rules.Capitalized)...)
+	problems = append(problems, footerProblems(m)...)
+
+	return problems
+}
+
+// descriptionProblems checks the description part of the subject
+func descriptionProblems(description string, capitalized bool) []string {
+	var problems []string
+
+	if strings.TrimSpace(description) == "" {
+		return []string{"the description after the colon is empty"}
+	}
+	if description != strings.TrimSpace(description) {
+		problems = append(problems, "remove extra spaces around the description")
+	}
+	if strings.HasSuffix(description, ".") {
+		problems = append(problems, "do not end the subject with a period")
+	}
+
+	first, _, _ := strings.Cut(strings.TrimSpace(description), " ")
+	switch runes := []rune(first); {
+	case !capitalized && startsCapitalized(first):
+		problems = append(problems, "start the description with a lowercase letter")
+	case capitalized && len(runes) > 0 && unicode.IsLower(runes[0]):
+		problems = append(problems, "start the description with a capital letter")
+	}
+	if nonImperative(first) {
+		problems = append(problems, fmt.Sprintf("use the imperative mood (\"add\", not \"added\" or \"adds\") instead of %q", first))
+	}
+
+	return problems
+}
+
+// footerProblems checks the footers and catches footers that did not parse as such
+func footerProblems(m Message) []string {
+	var problems []string
+	miscased := false
+
+	for _, f := range m.Footers {
+		if strings.EqualFold(f.Token, "BREAKING-CHANGE") && f.Token != "BREAKING-CHANGE" {
+			miscased = true
+		}
+	}
+
+	// Footers mixed with prose or written with spaces end up in the body
+	if paragraphs := splitParagraphs(m.Body); len(paragraphs) > 0 {
+		last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+		footerLines := 0
+		for _, line := range last {
+			switch {
+			case breakingPattern.MatchString(line):
+				miscased = miscased || !strings.HasPrefix(line, "BREAKING CHANGE:")
+				footerLines++
+			case footerPattern.MatchString(line):
+				footerLines++
+			}
+		}
+		if footerLines > 0 && footerLines < len(last) {
+			problems = append(problems, "put footers in a separate last paragraph, after a blank line")
+		}
+	}
+
+	if miscased {
+		problems = append(problems, "write the breaking change footer as \"BREAKING CHANGE: <description>\" in uppercase")
+	}
+	return problems
+}
+
+// StripWrapping removes code fences, quotes and introductory lines that models put around a message
+func StripWrapping(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+
+	lines := strings.Split(text, "\n")
+	if len(lines) > 1 && preamblePattern.MatchString(strings.TrimSpace(lines[0])) {
+		lines = lines[1:]
+		text = strings.TrimSpace(strings.Join(lines, "\n"))
+		lines = strings.Split(text, "\n")
+	}
+
+	// A message wrapped in a fence: keep what is between the opening line, with its language tag,
+	// and the closing fence, dropping any explanation after it
+	if strings.HasPrefix(lines[0], "```") {
+		lines = lines[1:]
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "```" {
+				lines = lines[:i]
+				break
+			}
+		}
+		text = strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+
+	// A single-line message wrapped in backticks or quotes
+	if !strings.Contains(text, "\n") && len(text) >= 2 {
+		for _, q := range []string{"`", `"`, "'"} {
+			if strings.HasPrefix(text, q) && strings.HasSuffix(text, q) {
+				text = strings.TrimSpace(text[1 : len(text)-1])
+				break
+			}
+		}
+	}
+
+	return text
+}
@@ -0,0 +1,77 @@
+package commitmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		rules Rules
+		want  []string // Substrings of the expected problems, in order
+	}{
+		{name: "valid", text: "feat(auth): add JWT validation"},
+		{name: "valid breaking with footers", text: "feat(api)!: drop v1 endpoints\n\nThe v1 API is gone.\n\nBREAKING CHANGE: clients must use v2\nRefs: #12"},
+		{name: "valid without scope", text: "docs: describe install steps"},
+		{name: "acronym first", text: "fix: JSON decoding of empty bodies"},
+		{name: "imperative exception", text: "feat: embed static assets"},
+		{name: "no type", text: "Add JWT validation", want: []string{"does not have the form"}},
+		{name: "unknown type", text: "feature: add login", want: []string{`unknown type "feature"`}},
+		{name: "configured types", text: "feat: add login", rules: Rules{Types: []string{"new", "fix"}}, want: []string{"use one of: new, fix"}},
+		{name: "empty scope", text: "fix(): handle nil", want: []string{"scope is empty"}},
+		{name: "bad scope", text: "fix(Auth Service): handle nil", want: []string{`invalid scope "Auth Service"`}},
+		{name: "unknown scope", text: "fix(web): handle nil", rules: Rules{Scopes: []string{"api", "cli"}}, want: []string{`unknown scope "web"`}},
+		{name: "too long", text: "fix: " + strings.Repeat("a", 70), want: []string{"75 characters long"}},
+		{name: "custom limit", text: "fix: handle nil pointer", rules: Rules{SubjectLimit: 10}, want: []string{"within 10"}},
+		{name: "period and case", text: "fix: Handle nil.", want: []string{"period", "lowercase"}},
+		{name: "capitalized style", text: "fix: Handle nil", rules: Rules{Capitalized: true}},
+		{name: "lowercase in capitalized style", text: "fix: handle nil", rules: Rules{Capitalized: true}, want: []string{"capital letter"}},
+		{name: "past tense", text: "fix: added nil check", want: []string{`instead of "added"`}},
+		{name: "third person", text: "fix: updates nil check", want: []string{`instead of "updates"`}},
+		{name: "gerund", text: "feat: adding login", want: []string{`instead of "adding"`}},
+		{name: "empty description", text: "fix: ", want: []string{"does not have the form"}},
+		{name: "footer mixed with prose", text: "fix: handle nil\n\nGuard the pointer.\nRefs: #12", want: []string{"separate last paragraph"}},
+		{name: "miscased breaking change", text: "fix: handle nil\n\nBreaking change: callers must check errors", want: []string{"in uppercase"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Validate(Parse(tt.text), tt.rules)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Validate() = %q, want %d problems", problems, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestStripWrapping(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain", text: "feat: add x\n\nBody.", want: "feat: add x\n\nBody."},
+		{name: "fence", text: "```\nfeat: add x\n```", want: "feat: add x"},
+		{name: "fence with language and trailer", text: "```text\nfeat: add x\n\nBody.\n```\nThis message follows the convention.", want: "feat: add x\n\nBody."},
+		{name: "preamble and fence", text: "Here is the commit message:\n\n```\nfix: handle nil\n```", want: "fix: handle nil"},
+		{name: "preamble only", text: "Commit message:\nfix: handle nil", want: "fix: handle nil"},
+		{name: "backticks", text: "`fix: handle nil`", want: "fix: handle nil"},
+		{name: "quotes", text: "\"fix: handle nil\"\n", want: "fix: handle nil"},
+		{name: "fence inside body kept", text: "feat: add x\n\n```\ncode\n```", want: "feat: add x\n\n```\ncode\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripWrapping(tt.text); got != tt.want {
+				t.Errorf("StripWrapping() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
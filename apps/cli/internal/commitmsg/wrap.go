@@ -0,0 +1,96 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// listItemPattern matches bullet and numbered list markers
+var listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+
+// Wrap reflows text to width columns
+// Paragraphs are kept apart, list items are wrapped with a hanging indent,
+// and indented or fenced code is left untouched
+func Wrap(text string, width int) string {
+	var out []string
+	var paragraph []string
+	indent := ""
+	inFence := false
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			out = append(out, wrapWords(strings.Join(paragraph, " "), width, indent)...)
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			flush()
+			out = append(out, line)
+			continue
+		}
+
+		switch m := listItemPattern.FindStringSubmatch(line); {
+		case trimmed == "":
+			flush()
+			out = append(out, "")
+		case m != nil:
+			flush()
+			indent = strings.Repeat(" ", len(m[0]))
+			paragraph = append(paragraph, strings.TrimRight(line, " "))
+		default:
+			if len(paragraph) == 0 {
+				indent = ""
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// wrapWords greedily fills lines of width columns, indenting continuation lines
+func wrapWords(text string, width int, indent string) []string {
+	// Keep the list marker and its leading spaces intact on the first line
+	lead := ""
+	if m := listItemPattern.FindString(text); m != "" {
+		lead, text = m, text[len(m):]
+	}
+
+	var lines []string
+	line := lead
+	lineLen := len([]rune(lead))
+	empty := true
+
+	for _, word := range strings.Fields(text) {
+		wordLen := len([]rune(word))
+		if !empty && lineLen+1+wordLen > width {
+			lines = append(lines, line)
+			line, lineLen, empty = indent, len(indent), true
+		}
+		if !empty {
+			line += " "
+			lineLen++
+		}
+		line += word
+		lineLen += wordLen
+		empty = false
+	}
+
+	return append(lines, line)
+}
+
+// wrapFooter wraps a long footer, indenting continuation lines so it still parses as one footer
+func wrapFooter(footer string, width int) string {
+	return strings.Join(wrapWords(footer, width, "  "), "\n")
+}
@@ -0,0 +1,57 @@
+package commitmsg
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{
+			name:  "paragraph",
+			text:  "one two three four five six",
+			width: 10,
+			want:  "one two\nthree four\nfive six",
+		},
+		{
+			name:  "keeps paragraphs apart",
+			text:  "aaa bbb\n\nccc",
+			width: 72,
+			want:  "aaa bbb\n\nccc",
+		},
+		{
+			name:  "reflows short lines",
+			text:  "aaa\nbbb\nccc",
+			width: 72,
+			want:  "aaa bbb ccc",
+		},
+		{
+			name:  "list items get a hanging indent",
+			text:  "- alpha beta gamma\n- delta",
+			width: 12,
+			want:  "- alpha beta\n  gamma\n- delta",
+		},
+		{
+			name:  "code is untouched",
+			text:  "see:\n\n    some very long indented code line that must stay",
+			width: 10,
+			want:  "see:\n\n    some very long indented code line that must stay",
+		},
+		{
+			name:  "long words are not split",
+			text:  "https://example.com/a/very/long/url",
+			width: 10,
+			want:  "https://example.com/a/very/long/url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Wrap(tt.text, tt.width); got != tt.want {
+				t.Errorf("Wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
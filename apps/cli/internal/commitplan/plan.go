@@ -0,0 +1,172 @@
+package commitplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// commitMarker starts a commit block in an edited plan
+	commitMarker = "=== commit ==="
+
+	// changesMarker separates the message of a commit block from its changes
+	changesMarker = "--- changes ---"
+)
+
+// Group is one planned commit
+type Group struct {
+	Message string
+	Changes []int // Change IDs, starting at 1
+}
+
+// Plan is an ordered list of commits that together cover some of the numbered changes
+type Plan struct {
+	Groups []Group
+}
+
+// response is the JSON plan requested from the model
+type response struct {
+	Commits []struct {
+		Message string `json:"message"`
+		Changes []int  `json:"changes"`
+	} `json:"commits"`
+}
+
+// ParseResponse reads the JSON plan returned by the model for n changes
+// Unknown and repeated change IDs are dropped, as are commits left without changes
+func ParseResponse(text string, n int) (Plan, error) {
+	// Models sometimes wrap the JSON in a fence or add a sentence around it
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Plan{}, fmt.Errorf("no JSON object in response")
+	}
+
+	var resp response
+	if err := json.Unmarshal([]byte(text[start:end+1]), &resp); err != nil {
+		return Plan{}, fmt.Errorf("invalid plan: %w", err)
+	}
+
+	var plan Plan
+	seen := map[int]bool{}
+	for _, c := range resp.Commits {
+		group := Group{Message: strings.TrimSpace(c.Message)}
+		for _, id := range c.Changes {
+			if id >= 1 && id <= n && !seen[id] {
+				seen[id] = true
+				group.Changes = append(group.Changes, id)
+			}
+		}
+		if len(group.Changes) > 0 && group.Message != "" {
+			plan.Groups = append(plan.Groups, group)
+		}
+	}
+
+	if len(plan.Groups) == 0 {
+		return Plan{}, fmt.Errorf("plan contains no commits")
+	}
+	return plan, nil
+}
+
+// Unassigned returns the IDs of the n changes that no commit contains
+func (p Plan) Unassigned(n int) []int {
+	assigned := map[int]bool{}
+	for _, g := range p.Groups {
+		for _, id := range g.Changes {
+			assigned[id] = true
+		}
+	}
+
+	var missing []int
+	for id := 1; id <= n; id++ {
+		if !assigned[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// Format renders the plan for editing, describing each change with label
+func (p Plan) Format(label func(id int) string) string {
+	var b strings.Builder
+	b.WriteString(`# Reorder the commit blocks to change the commit order, move change lines between
+# commits and edit the messages. Changes left out of every commit stay uncommitted.
+# Lines starting with '#' are ignored. Remove all commits to abort.
+`)
+
+	for _, g := range p.Groups {
+		fmt.Fprintf(&b, "\n%s\n%s\n%s\n", commitMarker, g.Message, changesMarker)
+		for _, id := range g.Changes {
+			fmt.Fprintf(&b, "%d %s\n", id, label(id))
+		}
+	}
+	return b.String()
+}
+
+// ParseEdited reads a plan for n changes in the format written by Format
+func ParseEdited(text string, n int) (Plan, error) {
+	var plan Plan
+	var message []string
+	var current *Group
+	inChanges := false
+	seen := map[int]bool{}
+
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		current.Message = strings.TrimSpace(strings.Join(message, "\n"))
+		switch {
+		case current.Message == "":
+			return fmt.Errorf("commit %d has no message", len(plan.Groups)+1)
+		case len(current.Changes) == 0:
+			return fmt.Errorf("commit %d has no changes", len(plan.Groups)+1)
+		}
+		plan.Groups = append(plan.Groups, *current)
+		return nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "#"):
+			continue
+		case trimmed == commitMarker:
+			if err := flush(); err != nil {
+				return Plan{}, err
+			}
+			current, message, inChanges = &Group{}, nil, false
+		case current == nil:
+			if trimmed != "" {
+				return Plan{}, fmt.Errorf("unexpected line before the first commit: %q", trimmed)
+			}
+		case trimmed == changesMarker:
+			inChanges = true
+		case !inChanges:
+			message = append(message, strings.TrimRight(line, " \t\r"))
+		case trimmed != "":
+			field, _, _ := strings.Cut(trimmed, " ")
+			id, err := strconv.Atoi(field)
+			if err != nil || id < 1 || id > n {
+				return Plan{}, fmt.Errorf("invalid change %q", trimmed)
+			}
+			if seen[id] {
+				return Plan{}, fmt.Errorf("change %d is listed more than once", id)
+			}
+			seen[id] = true
+			current.Changes = append(current.Changes, id)
+		}
+	}
+	if err := flush(); err != nil {
+		return Plan{}, err
+	}
+
+	return plan, nil
+}
+
+// Append adds a commit for changes at the end of the plan
+func (p *Plan) Append(message string, changes []int) {
+	p.Groups = append(p.Groups, Group{Message: message, Changes: slices.Clone(changes)})
+}
@@ -0,0 +1,114 @@
+package commitplan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []Group
+		wantErr bool
+	}{
+		{
+			name: "plain JSON",
+			text: `{"commits":[{"message":"feat: add a","changes":[1,3]},{"message":"fix: b","changes":[2]}]}`,
+			want: []Group{{Message: "feat: add a", Changes: []int{1, 3}}, {Message: "fix: b", Changes: []int{2}}},
+		},
+		{
+			name: "fenced with prose",
+			text: "Here is the plan:\n```json\n{\"commits\":[{\"message\":\"docs: x\",\"changes\":[1]}]}\n```",
+			want: []Group{{Message: "docs: x", Changes: []int{1}}},
+		},
+		{
+			name: "drops unknown, repeated and empty",
+			text: `{"commits":[{"message":"feat: a","changes":[1,9,1]},{"message":"fix: b","changes":[1]},{"message":"","changes":[2]}]}`,
+			want: []Group{{Message: "feat: a", Changes: []int{1}}},
+		},
+		{name: "no JSON", text: "feat: add a", wantErr: true},
+		{name: "no commits", text: `{"commits":[]}`, wantErr: true},
+		{name: "malformed", text: `{"commits":[{"message":}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParseResponse(tt.text, 3)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(plan.Groups, tt.want) {
+				t.Errorf("ParseResponse() = %+v, want %+v", plan.Groups, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnassigned(t *testing.T) {
+	plan := Plan{Groups: []Group{{Message: "a", Changes: []int{3, 1}}}}
+	if got := plan.Unassigned(4); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Unassigned() = %v, want [2 4]", got)
+	}
+}
+
+func TestFormatParseEdited_RoundTrip(t *testing.T) {
+	plan := Plan{Groups: []Group{
+		{Message: "feat(api): add login\n\nAdds the endpoint.", Changes: []int{1, 2}},
+		{Message: "docs: describe login", Changes: []int{3}},
+	}}
+
+	text := plan.Format(func(id int) string { return fmt.Sprintf("file%d.go", id) })
+	if !strings.Contains(text, "1 file1.go\n") {
+		t.Errorf("Format() should label changes:\n%s", text)
+	}
+
+	got, err := ParseEdited(text, 3)
+	if err != nil {
+		t.Fatalf("ParseEdited() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, plan) {
+		t.Errorf("ParseEdited(Format()) = %+v, want %+v", got, plan)
+	}
+}
+
+func TestParseEdited(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []Group
+		wantErr string
+	}{
+		{
+			name: "reordered and moved",
+			text: "=== commit ===\ndocs: b\n--- changes ---\n2 b.md\n1 a.go\n\n=== commit ===\nfeat: a\n--- changes ---\n3\n",
+			want: []Group{{Message: "docs: b", Changes: []int{2, 1}}, {Message: "feat: a", Changes: []int{3}}},
+		},
+		{name: "all removed", text: "# nothing left\n"},
+		{name: "missing message", text: "=== commit ===\n--- changes ---\n1\n", wantErr: "no message"},
+		{name: "missing changes", text: "=== commit ===\nfeat: a\n--- changes ---\n", wantErr: "no changes"},
+		{name: "duplicate", text: "=== commit ===\na\n--- changes ---\n1\n=== commit ===\nb\n--- changes ---\n1\n", wantErr: "more than once"},
+		{name: "unknown change", text: "=== commit ===\na\n--- changes ---\n7 x.go\n", wantErr: "invalid change"},
+		{name: "stray text", text: "hello\n=== commit ===\na\n--- changes ---\n1\n", wantErr: "unexpected line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := ParseEdited(tt.text, 3)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseEdited() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEdited() error = %v", err)
+			}
+			if !reflect.DeepEqual(plan.Groups, tt.want) {
+				t.Errorf("ParseEdited() = %+v, want %+v", plan.Groups, tt.want)
+			}
+		})
+	}
+}
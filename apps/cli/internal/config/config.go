@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -8,14 +9,32 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// Config represents user-configurable settings (non-security critical)
+// Config represents user-configurable settings
 type Config struct {
+	// Provider settings
+	Provider  string                    `yaml:"provider"` // Backend for requests, empty for the hosted ZIK API
+	Providers map[string]ProviderConfig `yaml:"providers,omitempty"`
+
 	// Model settings
 	Model       string  `yaml:"model"`
 	Temperature float64 `yaml:"temperature"`
 	MaxTokens   int     `yaml:"max_tokens"`
 	Streaming   bool    `yaml:"streaming"`
 
+	// RequestTimeout bounds a single non-streaming request including its retries, 0 disables
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+
+	// Reasoning settings
+	Thinking      bool `yaml:"thinking"`       // Ask the model to reason before answering
+	ShowReasoning bool `yaml:"show_reasoning"` // Print the reasoning before the answer instead of a one-line note
+
+	// Prompt settings
+	Instructions string `yaml:"instructions"` // Extra instructions added to every system prompt
+
+	// Profile settings
+	Profile  string             `yaml:"profile"` // Active profile, empty for none
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+
 	// Commit settings
 	Commit CommitConfig `yaml:"commit"`
 
@@ -25,16 +44,23 @@ type Config struct {
 
 // CommitConfig holds commit message generation settings
 type CommitConfig struct {
-	ConventionalCommits bool   `yaml:"conventional_commits"`
-	PreferredType       string `yaml:"preferred_type"`
-	AutoStage           bool   `yaml:"auto_stage"`
+	ConventionalCommits bool              `yaml:"conventional_commits"`
+	PreferredType       string            `yaml:"preferred_type"`
+	AutoStage           bool              `yaml:"auto_stage"`
+	MaxDiffTokens       int               `yaml:"max_diff_tokens"`  // Larger diffs are summarized per file, 0 uses the context budget
+	IncludeBody         bool              `yaml:"include_body"`     // Allow a body explaining the motivation
+	LinkIssues          bool              `yaml:"link_issues"`      // Add Refs/Closes footers inferred from the branch name
+	LearnStyle          bool              `yaml:"learn_style"`      // Follow the style of recent commit messages
+	Scopes              map[string]string `yaml:"scopes,omitempty"` // Scope for each path prefix, e.g. apps/cli: cli
 }
 
 // ChatConfig holds interactive chat settings
 type ChatConfig struct {
-	SaveHistory  bool          `yaml:"save_history"`
-	HistoryLimit int           `yaml:"history_limit"`
-	Timeout      time.Duration `yaml:"timeout"`
+	SaveHistory   bool          `yaml:"save_history"`
+	HistoryLimit  int           `yaml:"history_limit"`
+	Timeout       time.Duration `yaml:"timeout"`
+	ContextTokens int           `yaml:"context_tokens"` // Context window budget, 0 disables
+	Summarize     bool          `yaml:"summarize"`      // Summarize turns dropped from the context
 }
 
 // Default returns a config with sensible defaults
@@ -44,49 +70,51 @@ func Default() *Config {
 		Temperature: 0.7,
 		MaxTokens:   2000,
 		Streaming:   true,
+
+		RequestTimeout: 5 * time.Minute,
 		Commit: CommitConfig{
 			ConventionalCommits: true,
-			PreferredType:       "feat",
+			PreferredType:       "",
 			AutoStage:           false,
+			MaxDiffTokens:       12000,
+			IncludeBody:         true,
+			LinkIssues:          true,
+			LearnStyle:          true,
 		},
 		Chat: ChatConfig{
-			SaveHistory:  true,
-			HistoryLimit: 100,
-			Timeout:      30 * time.Second,
+			SaveHistory:   true,
+			HistoryLimit:  100,
+			Timeout:       30 * time.Second,
+			ContextTokens: 32000,
+			Summarize:     true,
 		},
 	}
 }
 
-// Load reads config from ~/.config/zik/config.yaml
-// Falls back to defaults if file doesn't exist
+// Load returns the effective config: defaults, then ~/.config/zik/config.yaml,
+// then the nearest project .zik.yaml, then the active profile, then ZIK_* environment variables
+// The model falls back to the default of the selected provider, and the result is validated
+// because environment variables bypass the checks done by Set
 func Load() (*Config, error) {
-	cfg := Default()
-
-	configPath, err := getConfigPath()
+	cfg, origins, err := LoadWithOrigins()
 	if err != nil {
-		return cfg, nil // Return defaults if config path cannot be determined
-	}
-
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return cfg, nil // Return defaults if config doesn't exist
+		return nil, err
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configPath)
-	if err != nil {
+	if err := cfg.ApplyProfile(origins); err != nil {
 		return nil, err
 	}
-
-	// Parse YAML
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	if err := cfg.ApplyProvider(origins); err != nil {
 		return nil, err
 	}
-
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
 	return cfg, nil
 }
 
 // Save writes config to ~/.config/zik/config.yaml
+// The file may hold provider API keys, so only the user can read it
 func (c *Config) Save() error {
 	configPath, err := getConfigPath()
 	if err != nil {
@@ -95,7 +123,7 @@ func (c *Config) Save() error {
 
 	// Ensure config directory exists
 	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0700); err != nil {
 		return err
 	}
 
@@ -105,16 +133,34 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	// Write to file
-	return os.WriteFile(configPath, data, 0644)
+	// Write to file, tightening the mode of a file created by an older version
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(configPath, 0600)
+}
+
+// Dir returns the directory holding ZIK configuration and local data
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "zik"), nil
+}
+
+// Path returns the path to the global config file
+func Path() (string, error) {
+	return getConfigPath()
 }
 
 // getConfigPath returns the path to the config file
 func getConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := Dir()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(home, ".config", "zik", "config.yaml"), nil
+	return filepath.Join(dir, "config.yaml"), nil
 }
@@ -25,11 +25,17 @@ func TestDefault(t *testing.T) {
 		{"MaxTokens", cfg.MaxTokens, 2000},
 		{"Streaming", cfg.Streaming, true},
 		{"ConventionalCommits", cfg.Commit.ConventionalCommits, true},
-		{"PreferredType", cfg.Commit.PreferredType, "feat"},
+		{"PreferredType", cfg.Commit.PreferredType, ""},
 		{"AutoStage", cfg.Commit.AutoStage, false},
+		{"MaxDiffTokens", cfg.Commit.MaxDiffTokens, 12000},
+		{"IncludeBody", cfg.Commit.IncludeBody, true},
+		{"LinkIssues", cfg.Commit.LinkIssues, true},
+		{"LearnStyle", cfg.Commit.LearnStyle, true},
 		{"SaveHistory", cfg.Chat.SaveHistory, true},
 		{"HistoryLimit", cfg.Chat.HistoryLimit, 100},
 		{"Timeout", cfg.Chat.Timeout, 30 * time.Second},
+		{"ContextTokens", cfg.Chat.ContextTokens, 32000},
+		{"Summarize", cfg.Chat.Summarize, true},
 	}
 
 	for _, tt := range tests {
@@ -166,8 +172,15 @@ func TestSave(t *testing.T) {
 
 	// Verify file was created
 	configPath := filepath.Join(tmpDir, ".config", "zik", "config.yaml")
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		t.Error("Save() did not create config file")
+	info, err := os.Stat(configPath)
+	if os.IsNotExist(err) {
+		t.Fatal("Save() did not create config file")
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("config file mode = %o, want 600", mode)
+	}
+	if dir, err := os.Stat(filepath.Dir(configPath)); err != nil || dir.Mode().Perm() != 0700 {
+		t.Errorf("config dir = %v, %v, want mode 700", dir, err)
 	}
 
 	// Load and verify
@@ -200,3 +213,20 @@ func TestGetConfigPath(t *testing.T) {
 		t.Errorf("getConfigPath() = %v, should end with %v", path, expectedSuffix)
 	}
 }
+
+func TestDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+
+	expected := filepath.Join(tmpDir, ".config", "zik")
+	if dir != expected {
+		t.Errorf("Dir() = %v, want %v", dir, expected)
+	}
+}
@@ -2,10 +2,10 @@ package config
 
 import "os"
 
-// API endpoint is hardcoded at compile time to prevent users from redirecting requests
-// to unauthorized servers. This ensures all requests go through the official ZIK API.
+// The hosted API endpoint is hardcoded at compile time and serves the built-in "zik" provider.
+// Other backends, such as a self-hosted zik-server or Ollama, are configured as providers.
 const (
-	// DefaultAPIEndpoint is the production API endpoint that cannot be overridden by users
+	// DefaultAPIEndpoint is the production API endpoint used by the default provider
 	DefaultAPIEndpoint = "https://zik-api.zarazaex.xyz"
 
 	// DefaultModel is the default AI model to use for requests
@@ -15,7 +15,7 @@ const (
 	Version = "0.1.0"
 )
 
-// GetAPIEndpoint returns the API endpoint, allowing override via ZIK_API_URL env var for development
+// GetAPIEndpoint returns the endpoint of the default provider, allowing override via ZIK_API_URL env var for development
 // In production builds, this env var check can be removed for security
 func GetAPIEndpoint() string {
 	// Allow override for development/testing purposes
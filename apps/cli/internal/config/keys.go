@@ -0,0 +1,274 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownKey is returned for dotted keys that do not exist in Config
+var ErrUnknownKey = errors.New("unknown config key")
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// CommitTypes lists the Conventional Commits types accepted for commit settings
+var CommitTypes = commitmsg.DefaultTypes
+
+// Keys returns every settable dotted key, e.g. "commit.conventional_commits"
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	return keys
+}
+
+// collectKeys walks struct fields and appends the dotted keys of scalar fields
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			collectKeys(field.Type, key+".", keys)
+			continue
+		}
+		if isScalar(field.Type) {
+			*keys = append(*keys, key)
+		}
+	}
+}
+
+// Get returns the value of a dotted key formatted for display
+// Keys naming a section, such as "commit" or "profiles", return that section as YAML
+// Provider API keys are masked
+func (c *Config) Get(key string) (string, error) {
+	value, err := c.Redacted().lookup(key)
+	if err != nil {
+		return "", err
+	}
+
+	if value.Kind() == reflect.Map || (value.Kind() == reflect.Struct && value.Type() != durationType) {
+		data, err := yaml.Marshal(value.Interface())
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(data), "\n"), nil
+	}
+
+	return formatValue(value), nil
+}
+
+// Set parses value according to the type of the dotted key and assigns it
+// The change is rejected if it leaves the config invalid
+func (c *Config) Set(key, value string) error {
+	field, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+	if !isScalar(field.Type()) {
+		return fmt.Errorf("%s is a section, set one of its keys instead", key)
+	}
+
+	parsed, err := parseValue(field.Type(), value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	previous := reflect.ValueOf(field.Interface())
+	field.Set(parsed)
+
+	if err := c.Validate(); err != nil {
+		field.Set(previous)
+		return err
+	}
+
+	return nil
+}
+
+// Unset reverts a dotted key, or a whole section, to its default value
+func (c *Config) Unset(key string) error {
+	field, err := c.lookup(key)
+	if err != nil {
+		return err
+	}
+
+	defaults, err := Default().lookup(key)
+	if err != nil {
+		return err
+	}
+
+	field.Set(defaults)
+	return nil
+}
+
+// Validate checks that all values are within their allowed ranges
+func (c *Config) Validate() error {
+	if _, err := c.ActiveProvider(); err != nil {
+		return fmt.Errorf("provider: %w", err)
+	}
+	for name, provider := range c.Providers {
+		if name == DefaultProvider {
+			return fmt.Errorf("providers.%s: the name is reserved for the hosted ZIK API", name)
+		}
+		if err := provider.Validate(); err != nil {
+			return fmt.Errorf("providers.%s: %w", name, err)
+		}
+	}
+	if c.Model == "" {
+		return fmt.Errorf("model must not be empty")
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", c.Temperature)
+	}
+	if c.MaxTokens <= 0 {
+		return fmt.Errorf("max_tokens must be positive, got %d", c.MaxTokens)
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("request_timeout must not be negative, got %s", c.RequestTimeout)
+	}
+	if c.Commit.PreferredType != "" && !slices.Contains(CommitTypes, c.Commit.PreferredType) {
+		return fmt.Errorf("commit.preferred_type must be one of %s, got %q", strings.Join(CommitTypes, ", "), c.Commit.PreferredType)
+	}
+	if c.Commit.MaxDiffTokens < 0 {
+		return fmt.Errorf("commit.max_diff_tokens must not be negative, got %d", c.Commit.MaxDiffTokens)
+	}
+	for prefix, scope := range c.Commit.Scopes {
+		if prefix == "" || strings.TrimSpace(scope) == "" {
+			return fmt.Errorf("commit.scopes: path %q must map to a non-empty scope", prefix)
+		}
+	}
+	if c.Chat.HistoryLimit < 0 {
+		return fmt.Errorf("chat.history_limit must not be negative, got %d", c.Chat.HistoryLimit)
+	}
+	if c.Chat.Timeout < 0 {
+		return fmt.Errorf("chat.timeout must not be negative, got %s", c.Chat.Timeout)
+	}
+	if c.Chat.ContextTokens < 0 {
+		return fmt.Errorf("chat.context_tokens must not be negative, got %d", c.Chat.ContextTokens)
+	}
+	if _, ok := c.Profiles[c.Profile]; c.Profile != "" && !ok {
+		return fmt.Errorf("profile %q does not exist", c.Profile)
+	}
+	for _, name := range c.ProfileNames() {
+		if err := c.Profiles[name].Validate(); err != nil {
+			return fmt.Errorf("profiles.%s: %w", name, err)
+		}
+		if _, err := c.LookupProvider(c.Profiles[name].Provider); err != nil {
+			return fmt.Errorf("profiles.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateContextBudget checks that the chat context window leaves room for the conversation after the reply
+// It is not part of Validate because a profile may raise max_tokens for commands that keep no history
+func (c *Config) ValidateContextBudget(origins Origins) error {
+	if c.Chat.ContextTokens > 0 && c.Chat.ContextTokens <= c.MaxTokens {
+		return fmt.Errorf("chat.context_tokens (%d, from %s) must be larger than max_tokens (%d, from %s)",
+			c.Chat.ContextTokens, origins["chat.context_tokens"], c.MaxTokens, origins["max_tokens"])
+	}
+	return nil
+}
+
+// lookup resolves a dotted key to the addressable field it names
+func (c *Config) lookup(key string) (reflect.Value, error) {
+	value := reflect.ValueOf(c).Elem()
+
+	for _, part := range strings.Split(key, ".") {
+		if value.Kind() != reflect.Struct || value.Type() == durationType {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+		}
+
+		found := false
+		for i := 0; i < value.NumField(); i++ {
+			if yamlName(value.Type().Field(i)) == part {
+				value = value.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, key)
+		}
+	}
+
+	return value, nil
+}
+
+// yamlName returns the YAML key of a struct field, or "" if it is not serialized
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// isScalar reports whether a field type can be set from a single string
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parseValue converts text into a value of type t
+func parseValue(t reflect.Type, text string) (reflect.Value, error) {
+	text = strings.TrimSpace(text)
+	value := reflect.New(t).Elem()
+
+	switch {
+	case t == durationType:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return value, fmt.Errorf("expected a duration such as 30s or 2m")
+		}
+		value.SetInt(int64(d))
+	case t.Kind() == reflect.String:
+		value.SetString(text)
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return value, fmt.Errorf("expected true or false")
+		}
+		value.SetBool(b)
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return value, fmt.Errorf("expected an integer")
+		}
+		value.SetInt(n)
+	case t.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return value, fmt.Errorf("expected a number")
+		}
+		value.SetFloat(f)
+	default:
+		return value, fmt.Errorf("unsupported type %s", t)
+	}
+
+	return value, nil
+}
+
+// formatValue renders a scalar value the way it is written in YAML
+func formatValue(value reflect.Value) string {
+	if value.Type() == durationType {
+		return time.Duration(value.Int()).String()
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+
+	for _, want := range []string{"model", "temperature", "commit.conventional_commits", "chat.timeout"} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("Keys() missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"commit", "chat", "profiles"} {
+		if slices.Contains(keys, unwanted) {
+			t.Errorf("Keys() should not include section %q", unwanted)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	cfg := Default()
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"model", DefaultModel},
+		{"temperature", "0.7"},
+		{"max_tokens", "2000"},
+		{"commit.conventional_commits", "true"},
+		{"chat.timeout", "30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get(%q) error = %v", tt.key, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGet_Section(t *testing.T) {
+	got, err := Default().Get("commit")
+	if err != nil {
+		t.Fatalf("Get(commit) error = %v", err)
+	}
+	if !strings.Contains(got, "conventional_commits: true") {
+		t.Errorf("Get(commit) = %q, want YAML section", got)
+	}
+}
+
+func TestGet_UnknownKey(t *testing.T) {
+	for _, key := range []string{"nope", "commit.nope", "model.sub", "chat.timeout.x"} {
+		if _, err := Default().Get(key); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("Get(%q) error = %v, want ErrUnknownKey", key, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	cfg := Default()
+
+	sets := map[string]string{
+		"model":                       "gpt-4",
+		"temperature":                 "1.5",
+		"max_tokens":                  "4000",
+		"streaming":                   "false",
+		"show_reasoning":              "true",
+		"commit.conventional_commits": "false",
+		"commit.preferred_type":       "fix",
+		"chat.timeout":                "2m",
+	}
+	for key, value := range sets {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%q, %q) error = %v", key, value, err)
+		}
+	}
+
+	if cfg.Model != "gpt-4" || cfg.Temperature != 1.5 || cfg.MaxTokens != 4000 || cfg.Streaming || !cfg.ShowReasoning {
+		t.Errorf("Set() did not update model settings: %+v", cfg)
+	}
+	if cfg.Commit.ConventionalCommits || cfg.Commit.PreferredType != "fix" {
+		t.Errorf("Set() did not update commit settings: %+v", cfg.Commit)
+	}
+	if cfg.Chat.Timeout != 2*time.Minute {
+		t.Errorf("Set() chat.timeout = %v, want 2m", cfg.Chat.Timeout)
+	}
+}
+
+func TestSet_Invalid(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"temperature", "2.5"},
+		{"temperature", "-1"},
+		{"temperature", "hot"},
+		{"max_tokens", "-5"},
+		{"max_tokens", "0"},
+		{"max_tokens", "1.5"},
+		{"streaming", "maybe"},
+		{"chat.timeout", "30"},
+		{"chat.timeout", "-1s"},
+		{"chat.history_limit", "-1"},
+		{"commit.max_diff_tokens", "-5"},
+		{"commit.preferred_type", "feature"},
+		{"commit", "x"},
+		{"nope", "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := Default()
+			before := *cfg
+
+			if err := cfg.Set(tt.key, tt.value); err == nil {
+				t.Errorf("Set(%q, %q) should fail", tt.key, tt.value)
+			}
+			if !reflect.DeepEqual(*cfg, before) {
+				t.Errorf("Set(%q, %q) modified config despite error", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestUnset(t *testing.T) {
+	cfg := Default()
+	cfg.Temperature = 1.2
+	cfg.Commit.PreferredType = "fix"
+	cfg.Commit.AutoStage = true
+	cfg.Commit.Scopes = map[string]string{"apps/cli": "cli"}
+
+	if err := cfg.Unset("temperature"); err != nil {
+		t.Fatalf("Unset(temperature) error = %v", err)
+	}
+	if cfg.Temperature != Default().Temperature {
+		t.Errorf("Unset(temperature) = %v, want default", cfg.Temperature)
+	}
+
+	if err := cfg.Unset("commit"); err != nil {
+		t.Fatalf("Unset(commit) error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Commit, Default().Commit) {
+		t.Errorf("Unset(commit) = %+v, want defaults", cfg.Commit)
+	}
+
+	if err := cfg.Unset("nope"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Unset(nope) error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default().Validate() error = %v", err)
+	}
+
+	cfg := Default()
+	cfg.Model = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject empty model")
+	}
+
+	cfg = Default()
+	cfg.Commit.Scopes = map[string]string{"apps/cli": ""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an empty scope")
+	}
+}
+
+func TestValidateContextBudget(t *testing.T) {
+	cfg := Default()
+	cfg.MaxTokens = cfg.Chat.ContextTokens
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, the context budget is only checked by chat", err)
+	}
+
+	origins := Origins{"max_tokens": {Layer: LayerProfile, Source: "deep"}}
+	err := cfg.ValidateContextBudget(origins)
+	if err == nil {
+		t.Fatal("ValidateContextBudget() should reject context_tokens <= max_tokens")
+	}
+	if !strings.Contains(err.Error(), "profile:deep") || !strings.Contains(err.Error(), "from default") {
+		t.Errorf("ValidateContextBudget() error = %v, want the origin of both values", err)
+	}
+
+	cfg.Chat.ContextTokens = 0
+	if err := cfg.ValidateContextBudget(origins); err != nil {
+		t.Errorf("ValidateContextBudget() error = %v, a disabled budget has nothing to check", err)
+	}
+}
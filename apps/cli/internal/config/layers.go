@@ -0,0 +1,235 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProjectFile is the name of the per-repository config file
+	ProjectFile = ".zik.yaml"
+
+	// EnvPrefix prefixes environment variables that override config keys
+	EnvPrefix = "ZIK_"
+)
+
+// Layer identifies a configuration source, in increasing order of precedence
+type Layer int
+
+const (
+	LayerDefault Layer = iota
+	LayerGlobal
+	LayerProject
+	LayerProfile
+	LayerEnv
+	LayerFlag
+)
+
+// String returns the layer name used in --show-origin output
+func (l Layer) String() string {
+	switch l {
+	case LayerGlobal:
+		return "global"
+	case LayerProject:
+		return "project"
+	case LayerProfile:
+		return "profile"
+	case LayerEnv:
+		return "env"
+	case LayerFlag:
+		return "flag"
+	default:
+		return "default"
+	}
+}
+
+// Origin records where an effective config value came from
+type Origin struct {
+	Layer  Layer
+	Source string // File path, profile name, environment variable or flag name
+}
+
+// String formats the origin as "layer:source", or just the layer for defaults
+func (o Origin) String() string {
+	if o.Source == "" {
+		return o.Layer.String()
+	}
+	return o.Layer.String() + ":" + o.Source
+}
+
+// Origins maps dotted keys to the origin of their effective value
+type Origins map[string]Origin
+
+// LoadWithOrigins merges the global config, the nearest project config and
+// ZIK_* environment variables on top of the defaults, recording the origin of every key
+// The active profile is not applied yet, so callers can still change it; see ApplyProfile
+func LoadWithOrigins() (*Config, Origins, error) {
+	cfg := Default()
+	origins := Origins{}
+	for _, key := range Keys() {
+		origins[key] = Origin{Layer: LayerDefault}
+	}
+
+	if path, err := getConfigPath(); err == nil {
+		if err := cfg.mergeFile(path, Origin{Layer: LayerGlobal, Source: path}, origins); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if path := ProjectPath(); path != "" {
+		if err := cfg.mergeFile(path, Origin{Layer: LayerProject, Source: path}, origins); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := cfg.mergeEnv(origins); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, origins, nil
+}
+
+// LoadGlobal reads only the global config file on top of the defaults
+// Use it when the result is written back with Save, so project and env overrides do not leak into the file
+func LoadGlobal() (*Config, error) {
+	cfg := Default()
+
+	path, err := getConfigPath()
+	if err != nil {
+		return cfg, nil
+	}
+
+	if err := cfg.mergeFile(path, Origin{Layer: LayerGlobal, Source: path}, nil); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ProjectPath returns the nearest .zik.yaml in the working directory or its parents, or "" if there is none
+func ProjectPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// EnvName returns the environment variable that overrides a dotted key, e.g. ZIK_COMMIT_PREFERRED_TYPE
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// mergeFile applies the YAML file at path, if it exists, and records origin for the keys it sets
+// origins may be nil when the caller does not track them
+func (c *Config) mergeFile(path string, origin Origin, origins Origins) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if origin.Layer == LayerProject {
+		if err := checkProjectKeys(flattenKeys(raw, "")); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if origins == nil {
+		return nil
+	}
+
+	for _, key := range flattenKeys(raw, "") {
+		// Unknown keys are ignored by the decoder, so they have no origin either
+		if _, ok := origins[key]; ok {
+			origins[key] = origin
+		}
+	}
+
+	return nil
+}
+
+// checkProjectKeys rejects keys that choose where requests and API keys are sent
+// A project file comes with the repository, so any clone could otherwise redirect code and secrets to its own host
+func checkProjectKeys(keys []string) error {
+	var denied []string
+	for _, key := range keys {
+		switch {
+		case key == "provider", key == "providers", strings.HasPrefix(key, "providers."):
+		case strings.HasPrefix(key, "profiles.") && strings.HasSuffix(key, ".provider"):
+		default:
+			continue
+		}
+		denied = append(denied, key)
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+
+	sort.Strings(denied)
+	return fmt.Errorf("%s cannot be set in a project config; configure providers in the global config", strings.Join(denied, ", "))
+}
+
+// mergeEnv applies ZIK_* environment variables named after config keys
+func (c *Config) mergeEnv(origins Origins) error {
+	for _, key := range Keys() {
+		name := EnvName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			continue
+		}
+
+		field, err := c.lookup(key)
+		if err != nil {
+			return err
+		}
+		parsed, err := parseValue(field.Type(), value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		field.Set(parsed)
+		origins[key] = Origin{Layer: LayerEnv, Source: name}
+	}
+
+	return nil
+}
+
+// flattenKeys returns the dotted paths of all leaf values in a parsed YAML mapping
+func flattenKeys(raw map[string]any, prefix string) []string {
+	var keys []string
+	for name, value := range raw {
+		key := prefix + name
+		if nested, ok := value.(map[string]any); ok {
+			keys = append(keys, flattenKeys(nested, key+".")...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
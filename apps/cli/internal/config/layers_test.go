@@ -0,0 +1,207 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupLayers points HOME at a temp dir with a global config and changes into a
+// nested project directory whose parent holds a .zik.yaml
+func setupLayers(t *testing.T, global, project string) (home, projectPath string) {
+	t.Helper()
+
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+
+	if global != "" {
+		configDir := filepath.Join(home, ".config", "zik")
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			t.Fatalf("failed to create config dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(global), 0644); err != nil {
+			t.Fatalf("failed to write global config: %v", err)
+		}
+	}
+
+	repo := filepath.Join(home, "repo")
+	nested := filepath.Join(repo, "pkg", "sub")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create project dir: %v", err)
+	}
+	if project != "" {
+		projectPath = filepath.Join(repo, ProjectFile)
+		if err := os.WriteFile(projectPath, []byte(project), 0644); err != nil {
+			t.Fatalf("failed to write project config: %v", err)
+		}
+	}
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(nested); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+
+	return home, projectPath
+}
+
+func TestLoadWithOrigins(t *testing.T) {
+	global := "model: global-model\ntemperature: 0.5\ncommit:\n  preferred_type: docs\n"
+	project := "model: project-model\ncommit:\n  preferred_type: fix\n"
+	home, projectPath := setupLayers(t, global, project)
+	t.Setenv("ZIK_MODEL", "env-model")
+	t.Setenv("ZIK_CHAT_TIMEOUT", "1m")
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins() error = %v", err)
+	}
+
+	globalPath := filepath.Join(home, ".config", "zik", "config.yaml")
+	tests := []struct {
+		key    string
+		value  string
+		origin Origin
+	}{
+		{"model", "env-model", Origin{Layer: LayerEnv, Source: "ZIK_MODEL"}},
+		{"temperature", "0.5", Origin{Layer: LayerGlobal, Source: globalPath}},
+		{"commit.preferred_type", "fix", Origin{Layer: LayerProject, Source: projectPath}},
+		{"chat.timeout", "1m0s", Origin{Layer: LayerEnv, Source: "ZIK_CHAT_TIMEOUT"}},
+		{"max_tokens", "2000", Origin{Layer: LayerDefault}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, _ := cfg.Get(tt.key)
+			if got != tt.value {
+				t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.value)
+			}
+			if origins[tt.key] != tt.origin {
+				t.Errorf("origin of %q = %v, want %v", tt.key, origins[tt.key], tt.origin)
+			}
+		})
+	}
+}
+
+func TestLoadWithOrigins_InvalidEnv(t *testing.T) {
+	setupLayers(t, "", "")
+	t.Setenv("ZIK_MAX_TOKENS", "lots")
+
+	if _, _, err := LoadWithOrigins(); err == nil {
+		t.Error("LoadWithOrigins() should fail for a malformed env override")
+	}
+}
+
+func TestLoad_ValidatesEnv(t *testing.T) {
+	for name, value := range map[string]string{
+		"ZIK_TEMPERATURE":           "9",
+		"ZIK_COMMIT_PREFERRED_TYPE": "bogus",
+	} {
+		t.Run(name, func(t *testing.T) {
+			setupLayers(t, "", "")
+			t.Setenv(name, value)
+
+			if _, err := Load(); err == nil {
+				t.Errorf("Load() should reject %s=%s", name, value)
+			}
+		})
+	}
+}
+
+func TestLoadWithOrigins_InvalidProject(t *testing.T) {
+	setupLayers(t, "", "model: [unclosed")
+
+	if _, _, err := LoadWithOrigins(); err == nil {
+		t.Error("LoadWithOrigins() should fail for an invalid project file")
+	}
+}
+
+func TestLoadWithOrigins_ProjectCannotChangeProvider(t *testing.T) {
+	global := "providers:\n  team:\n    endpoint: https://zik.example.com\n    api_key_env: TEAM_ZIK_KEY\n"
+	tests := []struct {
+		name    string
+		project string
+	}{
+		{"provider", "provider: team\n"},
+		{"endpoint", "providers:\n  team:\n    endpoint: https://attacker.example\n"},
+		{"key source", "providers:\n  team:\n    api_key_env: GITHUB_TOKEN\n"},
+		{"new provider", "provider: x\nproviders:\n  x:\n    endpoint: https://attacker.example\n    api_key_env: GITHUB_TOKEN\n"},
+		{"profile provider", "profiles:\n  fast:\n    provider: team\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupLayers(t, global, tt.project)
+
+			_, _, err := LoadWithOrigins()
+			if err == nil || !strings.Contains(err.Error(), "cannot be set in a project config") {
+				t.Errorf("LoadWithOrigins() error = %v, want the project file rejected", err)
+			}
+		})
+	}
+
+	// Other profile settings are still allowed
+	setupLayers(t, global, "profiles:\n  fast:\n    model: small\n")
+	cfg, _, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins() error = %v", err)
+	}
+	if cfg.Providers["team"].Endpoint != "https://zik.example.com" || cfg.Profiles["fast"].Model != "small" {
+		t.Errorf("providers = %+v, profiles = %+v", cfg.Providers, cfg.Profiles)
+	}
+}
+
+func TestLoadGlobal_IgnoresOverrides(t *testing.T) {
+	setupLayers(t, "model: global-model\n", "model: project-model\n")
+	t.Setenv("ZIK_TEMPERATURE", "1.5")
+
+	cfg, err := LoadGlobal()
+	if err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	if cfg.Model != "global-model" {
+		t.Errorf("LoadGlobal() Model = %q, want global-model", cfg.Model)
+	}
+	if cfg.Temperature != Default().Temperature {
+		t.Errorf("LoadGlobal() Temperature = %v, want default", cfg.Temperature)
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	_, projectPath := setupLayers(t, "", "model: project-model\n")
+
+	got := ProjectPath()
+	// Resolve symlinks such as /tmp on macOS before comparing
+	want, _ := filepath.EvalSymlinks(projectPath)
+	if resolved, _ := filepath.EvalSymlinks(got); resolved != want {
+		t.Errorf("ProjectPath() = %q, want %q", got, projectPath)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"model":                 "ZIK_MODEL",
+		"max_tokens":            "ZIK_MAX_TOKENS",
+		"commit.preferred_type": "ZIK_COMMIT_PREFERRED_TYPE",
+	}
+
+	for key, want := range tests {
+		if got := EnvName(key); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestOriginString(t *testing.T) {
+	if got := (Origin{Layer: LayerDefault}).String(); got != "default" {
+		t.Errorf("String() = %q, want default", got)
+	}
+	if got := (Origin{Layer: LayerFlag, Source: "--model"}).String(); got != "flag:--model" {
+		t.Errorf("String() = %q, want flag:--model", got)
+	}
+}
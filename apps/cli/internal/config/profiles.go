@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Profile is a named set of model and prompt settings applied on top of the config
+// Unset fields leave the underlying value unchanged
+type Profile struct {
+	Provider     string   `yaml:"provider,omitempty"`
+	Model        string   `yaml:"model,omitempty"`
+	Temperature  *float64 `yaml:"temperature,omitempty"`
+	MaxTokens    int      `yaml:"max_tokens,omitempty"`
+	Streaming    *bool    `yaml:"streaming,omitempty"`
+	Thinking     *bool    `yaml:"thinking,omitempty"`
+	Instructions string   `yaml:"instructions,omitempty"`
+}
+
+// ProfileNames returns the configured profile names in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile overlays the selected profile onto the config
+// Keys whose origin is an environment variable or a flag keep their value,
+// so per-invocation overrides still beat the profile
+func (c *Config) ApplyProfile(origins Origins) error {
+	if c.Profile == "" {
+		return nil
+	}
+
+	profile, ok := c.Profiles[c.Profile]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", c.Profile)
+	}
+
+	// claim reports whether the profile may set key, recording the profile as its origin
+	origin := Origin{Layer: LayerProfile, Source: c.Profile}
+	claim := func(key string) bool {
+		if origins[key].Layer >= LayerEnv {
+			return false
+		}
+		origins[key] = origin
+		return true
+	}
+
+	if profile.Provider != "" && claim("provider") {
+		c.Provider = profile.Provider
+	}
+	if profile.Model != "" && claim("model") {
+		c.Model = profile.Model
+	}
+	if profile.Temperature != nil && claim("temperature") {
+		c.Temperature = *profile.Temperature
+	}
+	if profile.MaxTokens != 0 && claim("max_tokens") {
+		c.MaxTokens = profile.MaxTokens
+	}
+	if profile.Streaming != nil && claim("streaming") {
+		c.Streaming = *profile.Streaming
+	}
+	if profile.Thinking != nil && claim("thinking") {
+		c.Thinking = *profile.Thinking
+	}
+	if profile.Instructions != "" && claim("instructions") {
+		c.Instructions = profile.Instructions
+	}
+
+	return nil
+}
+
+// Validate checks that the profile values are within their allowed ranges
+func (p Profile) Validate() error {
+	if p.Temperature != nil && (*p.Temperature < 0 || *p.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", *p.Temperature)
+	}
+	if p.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be positive, got %d", p.MaxTokens)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testProfiles() map[string]Profile {
+	temperature := 0.1
+	streaming, thinking := false, true
+	return map[string]Profile{
+		"fast": {Model: "fast-model", Temperature: &temperature, MaxTokens: 300},
+		"deep": {MaxTokens: 8000, Streaming: &streaming, Thinking: &thinking, Instructions: "Think it through"},
+	}
+}
+
+func TestProfileNames(t *testing.T) {
+	cfg := Default()
+	cfg.Profiles = testProfiles()
+
+	if got := cfg.ProfileNames(); !reflect.DeepEqual(got, []string{"deep", "fast"}) {
+		t.Errorf("ProfileNames() = %v, want [deep fast]", got)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	cfg := Default()
+	cfg.Profiles = testProfiles()
+	cfg.Profile = "fast"
+	origins := Origins{}
+
+	if err := cfg.ApplyProfile(origins); err != nil {
+		t.Fatalf("ApplyProfile() error = %v", err)
+	}
+
+	if cfg.Model != "fast-model" || cfg.Temperature != 0.1 || cfg.MaxTokens != 300 {
+		t.Errorf("ApplyProfile() = model %q temperature %v max_tokens %d", cfg.Model, cfg.Temperature, cfg.MaxTokens)
+	}
+	if !cfg.Streaming {
+		t.Error("ApplyProfile() should leave settings the profile does not set")
+	}
+	if origins["model"] != (Origin{Layer: LayerProfile, Source: "fast"}) {
+		t.Errorf("origin of model = %v, want profile:fast", origins["model"])
+	}
+}
+
+func TestApplyProfile_EnvAndFlagsWin(t *testing.T) {
+	cfg := Default()
+	cfg.Profiles = testProfiles()
+	cfg.Profile = "deep"
+	cfg.MaxTokens = 100
+	cfg.Streaming = true
+	origins := Origins{
+		"max_tokens": {Layer: LayerFlag, Source: "--max-tokens"},
+		"streaming":  {Layer: LayerEnv, Source: "ZIK_STREAMING"},
+	}
+
+	if err := cfg.ApplyProfile(origins); err != nil {
+		t.Fatalf("ApplyProfile() error = %v", err)
+	}
+
+	if cfg.MaxTokens != 100 {
+		t.Errorf("MaxTokens = %d, want flag value 100", cfg.MaxTokens)
+	}
+	if !cfg.Streaming {
+		t.Error("Streaming should keep the env value")
+	}
+	if cfg.Instructions != "Think it through" || !cfg.Thinking {
+		t.Errorf("Instructions = %q, Thinking = %v, want profile values", cfg.Instructions, cfg.Thinking)
+	}
+}
+
+func TestApplyProfile_Unknown(t *testing.T) {
+	cfg := Default()
+	cfg.Profile = "missing"
+
+	if err := cfg.ApplyProfile(Origins{}); err == nil {
+		t.Error("ApplyProfile() should fail for an unknown profile")
+	}
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	cfg := Default()
+	cfg.Profiles = testProfiles()
+
+	if err := cfg.Set("profile", "deep"); err != nil {
+		t.Errorf("Set(profile, deep) error = %v", err)
+	}
+	if err := cfg.Set("profile", "missing"); err == nil {
+		t.Error("Set(profile, missing) should fail")
+	}
+
+	temperature := 3.0
+	cfg.Profiles["hot"] = Profile{Temperature: &temperature}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject a profile with an out-of-range temperature")
+	}
+}
+
+func TestLoad_ProfileFromEnv(t *testing.T) {
+	global := "model: base\nprofiles:\n  fast:\n    model: fast-model\n"
+	setupLayers(t, global, "")
+	t.Setenv("ZIK_PROFILE", "fast")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Model != "fast-model" {
+		t.Errorf("Load() Model = %q, want fast-model", cfg.Model)
+	}
+}
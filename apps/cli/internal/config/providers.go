@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultProvider is the hosted ZIK API, used when no provider is selected
+	DefaultProvider = "zik"
+
+	// ProtocolOpenAI is the OpenAI chat completions protocol spoken by the ZIK API and zik-server
+	ProtocolOpenAI = "openai"
+
+	// ProtocolOllama is the Ollama /api/chat protocol
+	ProtocolOllama = "ollama"
+
+	// DefaultOllamaEndpoint is where Ollama listens unless configured otherwise
+	DefaultOllamaEndpoint = "http://localhost:11434"
+
+	// redactedKey replaces API keys in displayed config
+	redactedKey = "***"
+)
+
+// Protocols lists the protocols a provider may speak
+var Protocols = []string{ProtocolOpenAI, ProtocolOllama}
+
+// ProviderConfig describes a backend that serves chat requests
+type ProviderConfig struct {
+	Protocol  string   `yaml:"protocol,omitempty"`    // openai (default) or ollama
+	Endpoint  string   `yaml:"endpoint,omitempty"`    // Base URL; optional for Ollama
+	APIKey    string   `yaml:"api_key,omitempty"`     // Sent as a bearer token
+	APIKeyEnv string   `yaml:"api_key_env,omitempty"` // Environment variable holding the API key instead
+	Models    []string `yaml:"models,omitempty"`      // Models the provider serves; the first is its default
+}
+
+// Key returns the API key, read from APIKeyEnv when APIKey is empty
+func (p ProviderConfig) Key() string {
+	if p.APIKey == "" && p.APIKeyEnv != "" {
+		return os.Getenv(p.APIKeyEnv)
+	}
+	return p.APIKey
+}
+
+// Validate checks that the provider can be used
+func (p ProviderConfig) Validate() error {
+	if p.Protocol != "" && !slices.Contains(Protocols, p.Protocol) {
+		return fmt.Errorf("protocol must be one of %s, got %q", strings.Join(Protocols, ", "), p.Protocol)
+	}
+	if p.Endpoint == "" {
+		if p.Protocol != ProtocolOllama {
+			return fmt.Errorf("endpoint must not be empty")
+		}
+		return nil
+	}
+	if u, err := url.Parse(p.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint must be an http or https URL, got %q", p.Endpoint)
+	}
+	return nil
+}
+
+// ProviderNames returns the built-in and configured provider names in sorted order
+func (c *Config) ProviderNames() []string {
+	names := []string{DefaultProvider}
+	for name := range c.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProvider returns the provider called name
+// The hosted API is available as "zik" without any configuration
+func (c *Config) LookupProvider(name string) (ProviderConfig, error) {
+	if name == "" || name == DefaultProvider {
+		return ProviderConfig{Protocol: ProtocolOpenAI, Endpoint: GetAPIEndpoint()}, nil
+	}
+	provider, ok := c.Providers[name]
+	if !ok {
+		return ProviderConfig{}, fmt.Errorf("unknown provider %q", name)
+	}
+	if provider.Protocol == "" {
+		provider.Protocol = ProtocolOpenAI
+	}
+	if provider.Protocol == ProtocolOllama && provider.Endpoint == "" {
+		provider.Endpoint = DefaultOllamaEndpoint
+	}
+	return provider, nil
+}
+
+// ActiveProvider returns the selected provider
+func (c *Config) ActiveProvider() (ProviderConfig, error) {
+	return c.LookupProvider(c.Provider)
+}
+
+// ApplyProvider checks the selected provider and switches to its default model
+// when the model is not one it lists, unless a profile, env var or flag chose the model
+// It runs after ApplyProfile, since profiles may select both
+func (c *Config) ApplyProvider(origins Origins) error {
+	provider, err := c.ActiveProvider()
+	if err != nil {
+		return err
+	}
+
+	if len(provider.Models) > 0 && !slices.Contains(provider.Models, c.Model) && origins["model"].Layer < LayerProfile {
+		c.Model = provider.Models[0]
+		origins["model"] = Origin{Layer: LayerDefault, Source: "provider " + c.Provider}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config with provider API keys masked, for display
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if len(c.Providers) == 0 {
+		return &redacted
+	}
+
+	redacted.Providers = make(map[string]ProviderConfig, len(c.Providers))
+	for name, provider := range c.Providers {
+		if provider.APIKey != "" {
+			provider.APIKey = redactedKey
+		}
+		redacted.Providers[name] = provider
+	}
+	return &redacted
+}
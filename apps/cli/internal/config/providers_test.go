@@ -0,0 +1,157 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testProviders() map[string]ProviderConfig {
+	return map[string]ProviderConfig{
+		"team":  {Endpoint: "https://zik.internal.example", APIKeyEnv: "TEAM_ZIK_KEY"},
+		"local": {Protocol: ProtocolOllama, Models: []string{"llama3", "qwen2.5-coder"}},
+	}
+}
+
+func TestLookupProvider(t *testing.T) {
+	t.Setenv("ZIK_API_URL", "")
+	t.Setenv("TEAM_ZIK_KEY", "secret")
+	cfg := Default()
+	cfg.Providers = testProviders()
+
+	tests := []struct {
+		name string
+		want ProviderConfig
+		key  string
+	}{
+		{"", ProviderConfig{Protocol: ProtocolOpenAI, Endpoint: DefaultAPIEndpoint}, ""},
+		{"zik", ProviderConfig{Protocol: ProtocolOpenAI, Endpoint: DefaultAPIEndpoint}, ""},
+		{"team", ProviderConfig{Protocol: ProtocolOpenAI, Endpoint: "https://zik.internal.example", APIKeyEnv: "TEAM_ZIK_KEY"}, "secret"},
+		{"local", ProviderConfig{Protocol: ProtocolOllama, Endpoint: DefaultOllamaEndpoint, Models: []string{"llama3", "qwen2.5-coder"}}, ""},
+	}
+
+	for _, tt := range tests {
+		got, err := cfg.LookupProvider(tt.name)
+		if err != nil {
+			t.Fatalf("LookupProvider(%q) error = %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) || got.Key() != tt.key {
+			t.Errorf("LookupProvider(%q) = %+v with key %q, want %+v with key %q", tt.name, got, got.Key(), tt.want, tt.key)
+		}
+	}
+
+	if _, err := cfg.LookupProvider("missing"); err == nil {
+		t.Error("LookupProvider() should fail for an unknown provider")
+	}
+	if got := cfg.ProviderNames(); !reflect.DeepEqual(got, []string{"local", "team", "zik"}) {
+		t.Errorf("ProviderNames() = %v", got)
+	}
+}
+
+func TestApplyProvider_DefaultModel(t *testing.T) {
+	cfg := Default()
+	cfg.Providers = testProviders()
+	cfg.Provider = "local"
+
+	// A model the provider does not list switches to its first model
+	origins := Origins{"model": {Layer: LayerGlobal, Source: "config.yaml"}}
+	if err := cfg.ApplyProvider(origins); err != nil {
+		t.Fatalf("ApplyProvider() error = %v", err)
+	}
+	if cfg.Model != "llama3" {
+		t.Errorf("Model = %q, want llama3", cfg.Model)
+	}
+
+	// Listed models and models chosen for this invocation are kept
+	for _, tt := range []struct {
+		model  string
+		origin Origin
+	}{
+		{"qwen2.5-coder", Origin{Layer: LayerGlobal, Source: "config.yaml"}},
+		{"mistral", Origin{Layer: LayerFlag, Source: "--model"}},
+	} {
+		cfg.Model = tt.model
+		if err := cfg.ApplyProvider(Origins{"model": tt.origin}); err != nil {
+			t.Fatalf("ApplyProvider() error = %v", err)
+		}
+		if cfg.Model != tt.model {
+			t.Errorf("Model = %q, want %q from %s", cfg.Model, tt.model, tt.origin)
+		}
+	}
+}
+
+func TestApplyProfile_Provider(t *testing.T) {
+	cfg := Default()
+	cfg.Providers = testProviders()
+	cfg.Profiles = map[string]Profile{"offline": {Provider: "local"}}
+	cfg.Profile = "offline"
+	origins := Origins{}
+
+	if err := cfg.ApplyProfile(origins); err != nil {
+		t.Fatalf("ApplyProfile() error = %v", err)
+	}
+	if err := cfg.ApplyProvider(origins); err != nil {
+		t.Fatalf("ApplyProvider() error = %v", err)
+	}
+	if cfg.Provider != "local" || cfg.Model != "llama3" {
+		t.Errorf("provider = %q, model = %q, want local and llama3", cfg.Provider, cfg.Model)
+	}
+}
+
+func TestValidate_Providers(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"valid", func(c *Config) { c.Provider = "team" }, ""},
+		{"unknown provider", func(c *Config) { c.Provider = "missing" }, `unknown provider "missing"`},
+		{"reserved name", func(c *Config) { c.Providers["zik"] = ProviderConfig{Endpoint: "http://localhost:8802"} }, "reserved"},
+		{"bad protocol", func(c *Config) { c.Providers["team"] = ProviderConfig{Protocol: "grpc", Endpoint: "http://x"} }, "protocol must be one of"},
+		{"missing endpoint", func(c *Config) { c.Providers["team"] = ProviderConfig{} }, "endpoint must not be empty"},
+		{"bad endpoint", func(c *Config) { c.Providers["team"] = ProviderConfig{Endpoint: "localhost:8802"} }, "http or https URL"},
+		{"profile with unknown provider", func(c *Config) { c.Profiles = map[string]Profile{"p": {Provider: "missing"}} }, "profiles.p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Providers = testProviders()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Providers = map[string]ProviderConfig{
+		"team":  {Endpoint: "https://zik.internal.example", APIKey: "secret"},
+		"local": {Protocol: ProtocolOllama},
+	}
+
+	if got := cfg.Redacted().Providers["team"].APIKey; got != "***" {
+		t.Errorf("Redacted() api_key = %q, want ***", got)
+	}
+	if cfg.Providers["team"].APIKey != "secret" {
+		t.Error("Redacted() should not change the original config")
+	}
+
+	section, err := cfg.Get("providers")
+	if err != nil {
+		t.Fatalf("Get(providers) error = %v", err)
+	}
+	if strings.Contains(section, "secret") || !strings.Contains(section, `api_key: '***'`) {
+		t.Errorf("Get(providers) = %q, want the key masked", section)
+	}
+}
@@ -0,0 +1,231 @@
+package diff
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/scope"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+// Kind classifies a changed file by how its contents should be sent to the model
+type Kind int
+
+const (
+	KindSource Kind = iota
+	KindLockfile
+	KindGenerated
+	KindBinary
+)
+
+// String returns a human readable name for the kind
+func (k Kind) String() string {
+	switch k {
+	case KindLockfile:
+		return "lockfile"
+	case KindGenerated:
+		return "generated file"
+	case KindBinary:
+		return "binary file"
+	default:
+		return "source file"
+	}
+}
+
+// lockfiles lists dependency lock files by base name
+var lockfiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"flake.lock":          true,
+	"mix.lock":            true,
+	"pubspec.lock":        true,
+	"Podfile.lock":        true,
+}
+
+// generatedPatterns matches base names of generated code
+var generatedPatterns = []string{
+	"*.pb.go", "*_pb2.py", "*.pb.ts", "*_gen.go", "*.gen.go", "*.gen.ts", "zz_generated*",
+	"*.min.js", "*.min.css", "*.map", "*.snap",
+}
+
+// generatedDirs lists directories whose contents are vendored or generated at any depth
+var generatedDirs = []string{"vendor", "node_modules", "__generated__"}
+
+// outputDirs lists build output directories, recognised only at the repository root or the root of a
+// workspace package such as packages/ui/dist, since paths like tools/build/main.go hold real source
+var outputDirs = []string{"dist", "build"}
+
+// File is the diff of a single file
+type File struct {
+	Path    string
+	Kind    Kind
+	Added   int
+	Deleted int
+	Text    string // Full diff section, starting with the "diff --git" line
+}
+
+// Parse splits unified git diff output into per-file sections
+func Parse(diff string) []File {
+	var files []File
+	var current *File
+	var body strings.Builder
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimRight(body.String(), "\n")
+		current.Kind = classify(current.Path, current.Kind, current.Text)
+		files = append(files, *current)
+		body.Reset()
+	}
+
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &File{Path: headerPath(line)}
+			inHunk = false
+		}
+		if current == nil {
+			continue
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk && strings.HasPrefix(line, "+++ ") && line != "+++ /dev/null":
+			current.Path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case !inHunk && (strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch"):
+			current.Kind = KindBinary
+		case inHunk && strings.HasPrefix(line, "+"):
+			current.Added++
+		case inHunk && strings.HasPrefix(line, "-"):
+			current.Deleted++
+		}
+	}
+	flush()
+
+	return files
+}
+
+// headerPath extracts the new path from a "diff --git a/x b/y" line
+func headerPath(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+3:]
+	}
+	return rest
+}
+
+// classify decides how a file should be treated, keeping a binary marker found while parsing
+func classify(filePath string, kind Kind, text string) Kind {
+	if kind == KindBinary {
+		return kind
+	}
+
+	base := path.Base(filePath)
+	if lockfiles[base] {
+		return KindLockfile
+	}
+	for _, pattern := range generatedPatterns {
+		if matched, _ := path.Match(pattern, base); matched {
+			return KindGenerated
+		}
+	}
+	for _, dir := range generatedDirs {
+		if strings.HasPrefix(filePath, dir+"/") || strings.Contains(filePath, "/"+dir+"/") {
+			return KindGenerated
+		}
+	}
+	if inOutputDir(filePath) {
+		return KindGenerated
+	}
+
+	// Go and other toolchains mark generated files with a header comment
+	if head := firstLines(text, 40); (strings.Contains(head, "Code generated") && strings.Contains(head, "DO NOT EDIT")) || strings.Contains(head, "@generated") {
+		return KindGenerated
+	}
+
+	return KindSource
+}
+
+// inOutputDir reports whether filePath lies in a build output directory at the repository or package root
+func inOutputDir(filePath string) bool {
+	parts := strings.Split(filePath, "/")
+	if len(parts) > 1 && slices.Contains(outputDirs, parts[0]) {
+		return true
+	}
+	return len(parts) > 3 && slices.Contains(scope.WorkspaceDirs, parts[0]) && slices.Contains(outputDirs, parts[2])
+}
+
+// firstLines returns the first n lines of text
+func firstLines(text string, n int) string {
+	lines := strings.SplitN(text, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Stubbed reports whether the file contents are replaced by a one-line summary
+func (f File) Stubbed() bool {
+	return f.Kind != KindSource
+}
+
+// Stub returns the diff header followed by a note describing the omitted contents
+func (f File) Stub() string {
+	header, _, _ := strings.Cut(f.Text, "\n")
+	if f.Kind == KindBinary {
+		return fmt.Sprintf("%s\n[%s changed, contents omitted]", header, f.Kind)
+	}
+	return fmt.Sprintf("%s\n[%s changed: +%d -%d lines, contents omitted]", header, f.Kind, f.Added, f.Deleted)
+}
+
+// Compact returns the diff as sent to the model, with lockfiles, generated and binary files stubbed
+func (f File) Compact() string {
+	if f.Stubbed() {
+		return f.Stub()
+	}
+	return f.Text
+}
+
+// Render joins the compact form of files into a single diff
+func Render(files []File) string {
+	parts := make([]string, 0, len(files))
+	for _, f := range files {
+		parts = append(parts, f.Compact())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Truncate keeps the leading lines of text that fit in maxTokens and notes how many were cut
+func Truncate(text string, maxTokens int) string {
+	if tokens.Estimate(text) <= maxTokens {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	used := 0
+	for i, line := range lines {
+		used += tokens.Estimate(line + "\n")
+		if used > maxTokens {
+			return strings.Join(lines[:i], "\n") + fmt.Sprintf("\n[... %d more lines omitted]", len(lines)-i)
+		}
+	}
+	return text
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+const sampleDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
++import "fmt"
+-var x = 1
++var x = 2
+diff --git a/go.sum b/go.sum
+index 3333333..4444444 100644
+--- a/go.sum
++++ b/go.sum
+@@ -1,2 +1,3 @@
++example.com/a v1.0.0 h1:abc
++example.com/a v1.0.0/go.mod h1:def
+-example.com/a v0.9.0 h1:old
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..5555555
+Binary files /dev/null and b/logo.png differ
+diff --git a/api/types.go b/api/types.go
+index 6666666..7777777 100644
+--- a/api/types.go
++++ b/api/types.go
+@@ -1,2 +1,2 @@
+-// Code generated by tool. DO NOT EDIT.
++// Code generated by tool. DO NOT EDIT.
++type T struct{}
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 8888888..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone`
+
+func TestParse(t *testing.T) {
+	files := Parse(sampleDiff)
+
+	expected := []struct {
+		path    string
+		kind    Kind
+		added   int
+		deleted int
+	}{
+		{"main.go", KindSource, 2, 1},
+		{"go.sum", KindLockfile, 2, 1},
+		{"logo.png", KindBinary, 0, 0},
+		{"api/types.go", KindGenerated, 2, 1},
+		{"old.txt", KindSource, 0, 1},
+	}
+
+	if len(files) != len(expected) {
+		t.Fatalf("Parse() returned %d files, want %d", len(files), len(expected))
+	}
+
+	for i, want := range expected {
+		got := files[i]
+		if got.Path != want.path || got.Kind != want.kind || got.Added != want.added || got.Deleted != want.deleted {
+			t.Errorf("file %d = {%s %s +%d -%d}, want {%s %s +%d -%d}",
+				i, got.Path, got.Kind, got.Added, got.Deleted, want.path, want.kind, want.added, want.deleted)
+		}
+	}
+
+	if !strings.HasPrefix(files[0].Text, "diff --git a/main.go b/main.go") || !strings.HasSuffix(files[0].Text, "+var x = 2") {
+		t.Errorf("Parse() Text = %q, want the full file section", files[0].Text)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	if files := Parse(""); len(files) != 0 {
+		t.Errorf("Parse(\"\") = %v, want no files", files)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		path string
+		want Kind
+	}{
+		{"cmd/main.go", KindSource},
+		{"web/package-lock.json", KindLockfile},
+		{"Cargo.lock", KindLockfile},
+		{"proto/user.pb.go", KindGenerated},
+		{"static/app.min.js", KindGenerated},
+		{"vendor/github.com/x/y.go", KindGenerated},
+		{"web/node_modules/x/index.js", KindGenerated},
+		{"builder.go", KindSource},
+		{"dist/app.js", KindGenerated},
+		{"packages/ui/build/index.js", KindGenerated},
+		{"tools/build/main.go", KindSource},
+		{".github/build/release.sh", KindSource},
+		{"internal/dist/dist.go", KindSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := classify(tt.path, KindSource, ""); got != tt.want {
+				t.Errorf("classify(%q) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_StubsNonSource(t *testing.T) {
+	rendered := Render(Parse(sampleDiff))
+
+	if !strings.Contains(rendered, "+var x = 2") {
+		t.Error("Render() should keep source file contents")
+	}
+	if strings.Contains(rendered, "example.com/a") {
+		t.Error("Render() should omit lockfile contents")
+	}
+	if !strings.Contains(rendered, "diff --git a/go.sum b/go.sum\n[lockfile changed: +2 -1 lines, contents omitted]") {
+		t.Errorf("Render() missing lockfile stub:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "[binary file changed, contents omitted]") {
+		t.Error("Render() missing binary stub")
+	}
+	if strings.Contains(rendered, "type T struct") {
+		t.Error("Render() should omit generated file contents")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	text := strings.Repeat("line of text\n", 100)
+
+	if got := Truncate("short", 100); got != "short" {
+		t.Errorf("Truncate() changed text that fits: %q", got)
+	}
+
+	got := Truncate(text, 50)
+	if !strings.Contains(got, "more lines omitted]") {
+		t.Error("Truncate() should note omitted lines")
+	}
+	if tokens.Estimate(got) > 60 {
+		t.Errorf("Truncate() result is ~%d tokens, want about 50", tokens.Estimate(got))
+	}
+}
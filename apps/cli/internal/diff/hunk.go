@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Change is a part of a diff that can be applied on its own: one hunk of a modified file,
+// or a whole file when it is added, deleted, renamed, binary or only changes mode
+type Change struct {
+	File  File
+	Hunk  string // Hunk text starting with its "@@" line, empty for whole-file changes
+	Index int    // Position of the hunk within the file, starting at 1
+	Count int    // Number of hunks in the file
+}
+
+// Whole reports whether the change covers the entire file
+func (c Change) Whole() bool {
+	return c.Hunk == ""
+}
+
+// Label describes the change for listings, e.g. "main.go (hunk 2/3)"
+func (c Change) Label() string {
+	if c.Whole() || c.Count == 1 {
+		return c.File.Path
+	}
+	return fmt.Sprintf("%s (hunk %d/%d)", c.File.Path, c.Index, c.Count)
+}
+
+// Text returns the change as a patch for git apply
+func (c Change) Text() string {
+	if c.Whole() {
+		return c.File.Text
+	}
+	return c.File.Header() + "\n" + c.Hunk
+}
+
+// Compact returns the change as sent to the model, with stubbed files reduced to a note
+func (c Change) Compact() string {
+	if c.File.Stubbed() {
+		return c.File.Stub()
+	}
+	return c.Text()
+}
+
+// Header returns the lines of the file diff before its first hunk
+func (f File) Header() string {
+	header, _ := f.split()
+	return header
+}
+
+// Hunks returns the "@@" sections of the file diff
+func (f File) Hunks() []string {
+	_, hunks := f.split()
+	return hunks
+}
+
+// split separates the file header from its hunks
+func (f File) split() (string, []string) {
+	lines := strings.Split(f.Text, "\n")
+	var header []string
+	var hunks []string
+	var current []string
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			if current != nil {
+				hunks = append(hunks, strings.Join(current, "\n"))
+			} else {
+				header = lines[:i]
+			}
+			current = []string{line}
+			continue
+		}
+		if current != nil {
+			current = append(current, line)
+		}
+	}
+
+	if current == nil {
+		return f.Text, nil
+	}
+	return strings.Join(header, "\n"), append(hunks, strings.Join(current, "\n"))
+}
+
+// splittable reports whether the hunks of a file diff can be applied independently
+// Creations, deletions, renames, copies and mode changes must be applied in one piece
+func (f File) splittable() bool {
+	if f.Kind == KindBinary {
+		return false
+	}
+	for _, line := range strings.Split(f.Header(), "\n") {
+		for _, prefix := range []string{"new file mode", "deleted file mode", "rename from", "copy from", "old mode"} {
+			if strings.HasPrefix(line, prefix) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Changes splits files into independently applicable changes, in diff order
+func Changes(files []File) []Change {
+	var changes []Change
+	for _, f := range files {
+		hunks := f.Hunks()
+		if len(hunks) == 0 || !f.splittable() {
+			changes = append(changes, Change{File: f, Index: 1, Count: 1})
+			continue
+		}
+		for i, hunk := range hunks {
+			changes = append(changes, Change{File: f, Hunk: hunk, Index: i + 1, Count: len(hunks)})
+		}
+	}
+	return changes
+}
+
+// Patch joins changes into a patch for git apply
+// Hunks of the same file share one header and are kept in their original order
+func Patch(changes []Change) string {
+	var order []string
+	byFile := map[string][]Change{}
+	for _, c := range changes {
+		if _, ok := byFile[c.File.Path]; !ok {
+			order = append(order, c.File.Path)
+		}
+		byFile[c.File.Path] = append(byFile[c.File.Path], c)
+	}
+
+	var b strings.Builder
+	for _, path := range order {
+		group := byFile[path]
+		if group[0].Whole() {
+			b.WriteString(group[0].File.Text)
+			b.WriteString("\n")
+			continue
+		}
+
+		slices.SortFunc(group, func(x, y Change) int { return x.Index - y.Index })
+		b.WriteString(group[0].File.Header())
+		b.WriteString("\n")
+		for _, c := range group {
+			b.WriteString(c.Hunk)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
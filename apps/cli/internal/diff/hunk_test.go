@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const hunkDiff = `diff --git a/app.go b/app.go
+index 1111111..2222222 100644
+--- a/app.go
++++ b/app.go
+@@ -1,3 +1,3 @@
+ package app
+-const a = 1
++const a = 2
+@@ -20,3 +20,4 @@ func run() {
+ 	start()
++	stop()
+ }
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package app
++// new
+diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`
+
+func TestChanges(t *testing.T) {
+	changes := Changes(Parse(hunkDiff))
+
+	labels := make([]string, 0, len(changes))
+	for _, c := range changes {
+		labels = append(labels, c.Label())
+	}
+	want := []string{"app.go (hunk 1/2)", "app.go (hunk 2/2)", "new.go", "run.sh"}
+	if strings.Join(labels, ",") != strings.Join(want, ",") {
+		t.Fatalf("labels = %v, want %v", labels, want)
+	}
+
+	if changes[0].Whole() || !changes[2].Whole() || !changes[3].Whole() {
+		t.Error("only hunks of modified files should be split")
+	}
+	if !strings.HasPrefix(changes[1].Hunk, "@@ -20,3 +20,4 @@") {
+		t.Errorf("second hunk = %q", changes[1].Hunk)
+	}
+	if !strings.HasPrefix(changes[1].Text(), "diff --git a/app.go b/app.go\n") || strings.Contains(changes[1].Text(), "const a") {
+		t.Errorf("hunk text should repeat the file header and contain only its hunk:\n%s", changes[1].Text())
+	}
+}
+
+func TestPatch(t *testing.T) {
+	changes := Changes(Parse(hunkDiff))
+
+	// Hunks keep their file order and share one header, whatever order they are given in
+	patch := Patch([]Change{changes[3], changes[1], changes[0]})
+	want := `diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+diff --git a/app.go b/app.go
+index 1111111..2222222 100644
+--- a/app.go
++++ b/app.go
+@@ -1,3 +1,3 @@
+ package app
+-const a = 1
++const a = 2
+@@ -20,3 +20,4 @@ func run() {
+ 	start()
++	stop()
+ }
+`
+	if patch != want {
+		t.Errorf("Patch() =\n%s\nwant\n%s", patch, want)
+	}
+
+	if got := Patch(changes[1:2]); strings.Count(got, "\n@@") != 1 || !strings.Contains(got, "stop()") {
+		t.Errorf("single hunk patch =\n%s", got)
+	}
+}
+
+func TestFileHeader_NoHunks(t *testing.T) {
+	f := Parse(hunkDiff)[2]
+	if f.Header() != f.Text || len(f.Hunks()) != 0 {
+		t.Errorf("mode-only change should be all header, got %d hunks", len(f.Hunks()))
+	}
+}
@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// fakeEditor writes a script that appends a line to the file it is given
+func fakeEditor(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script editor not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	script := "#!/bin/sh\necho edited >> \"$1\"\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake editor: %v", err)
+	}
+	return path
+}
+
+func TestCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+
+	if got := Command(); !reflect.DeepEqual(got, []string{"code", "--wait"}) {
+		t.Errorf("Command() = %v, want [code --wait]", got)
+	}
+
+	t.Setenv("VISUAL", "nano")
+	if got := Command(); !reflect.DeepEqual(got, []string{"nano"}) {
+		t.Errorf("Command() = %v, want VISUAL to take precedence", got)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := Command(); len(got) != 1 {
+		t.Errorf("Command() = %v, want a default editor", got)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", fakeEditor(t))
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("original\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if err := Open(path); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "original\nedited\n" {
+		t.Errorf("file after Open() = %q", string(data))
+	}
+}
+
+func TestOpen_Failure(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "false")
+
+	if err := Open(filepath.Join(t.TempDir(), "file.txt")); err == nil {
+		t.Error("Open() should fail when the editor exits non-zero")
+	}
+}
+
+func TestEdit(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", fakeEditor(t))
+
+	got, err := Edit("message\n", "zik-*.txt")
+	if err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	if got != "message\nedited\n" {
+		t.Errorf("Edit() = %q, want %q", got, "message\nedited\n")
+	}
+}
@@ -41,6 +41,20 @@ func (c *Client) GetDiffAll() (string, error) {
 	return string(output), nil
 }
 
+// GetDiffRange returns the diff for a revision range such as "main..HEAD"
+func (c *Client) GetDiffRange(revRange string) (string, error) {
+	if revRange == "" || strings.HasPrefix(revRange, "-") {
+		return "", fmt.Errorf("invalid revision range %q", revRange)
+	}
+
+	cmd := exec.Command("git", "diff", revRange, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff for %s: %w", revRange, err)
+	}
+	return string(output), nil
+}
+
 // Commit creates a commit with the given message
 func (c *Client) Commit(message string) error {
 	cmd := exec.Command("git", "commit", "-m", message)
@@ -79,3 +93,154 @@ func (c *Client) GetBranch() (string, error) {
 	}
 	return strings.TrimSpace(string(output)), nil
 }
+
+// HooksDir returns the absolute path of the hooks directory, honouring core.hooksPath
+func (c *Client) HooksDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate hooks directory: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetPatchAll returns all changes against HEAD as a patch that git apply accepts, binary files included
+func (c *Client) GetPatchAll() (string, error) {
+	cmd := exec.Command("git", "diff", "HEAD", "--binary")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
+
+// GetHead returns the commit hash of HEAD
+func (c *Client) GetHead() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// WriteTree saves the index as a tree object and returns its hash
+func (c *Client) WriteTree() (string, error) {
+	cmd := exec.Command("git", "write-tree")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to save index: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ReadTree replaces the index with the contents of tree, leaving the working tree untouched
+func (c *Client) ReadTree(tree string) error {
+	return runGit("failed to restore index", "read-tree", tree)
+}
+
+// ResetSoft moves the current branch to rev, keeping the index and working tree
+func (c *Client) ResetSoft(rev string) error {
+	return runGit("failed to reset", "reset", "--soft", "-q", rev)
+}
+
+// ApplyCached applies patch to the index only
+func (c *Client) ApplyCached(patch string) error {
+	cmd := exec.Command("git", "apply", "--cached", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to stage changes: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// runGit executes a git command, reporting its stderr on failure
+func runGit(action string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %s", action, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// GetLog returns the full messages of the last n non-merge commits, newest first
+func (c *Client) GetLog(n int) ([]string, error) {
+	return c.log(fmt.Sprintf("-n%d", n))
+}
+
+// GetLogRange returns the full messages of the non-merge commits in a revision range such as "main..HEAD", newest first
+func (c *Client) GetLogRange(revRange string) ([]string, error) {
+	if revRange == "" || strings.HasPrefix(revRange, "-") {
+		return nil, fmt.Errorf("invalid revision range %q", revRange)
+	}
+	return c.log(revRange, "--")
+}
+
+// log returns the messages of the non-merge commits selected by args
+func (c *Client) log(args ...string) ([]string, error) {
+	cmd := exec.Command("git", append([]string{"log", "--no-merges", "--format=%B%x00"}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	var messages []string
+	for _, message := range strings.Split(string(output), "\x00") {
+		if message = strings.TrimSpace(message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// LatestTag returns the most recent tag reachable from rev
+// Tags on rev itself are skipped, so for a release tag the previous release is returned
+func (c *Client) LatestTag(rev string) (string, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return "", fmt.Errorf("invalid revision %q", rev)
+	}
+
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if output, err := exec.Command("git", "tag", "--points-at", rev).Output(); err == nil {
+		for _, tag := range strings.Fields(string(output)) {
+			args = append(args, "--exclude", tag)
+		}
+	}
+	cmd := exec.Command("git", append(args, rev)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no tag is reachable from %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DefaultBranch returns the branch pull requests usually target: the remote's HEAD, or main or master
+func (c *Client) DefaultBranch() (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	for _, branch := range []string{"main", "master"} {
+		if exec.Command("git", "rev-parse", "--verify", "--quiet", branch).Run() == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find a default branch, use --base")
+}
+
+// TopLevel returns the absolute path of the root of the working tree
+func (c *Client) TopLevel() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate repository root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
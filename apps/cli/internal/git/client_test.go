@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -25,8 +26,17 @@ func setupTestRepo(t *testing.T) (string, func()) {
 	}
 
 	// Configure git user for commits
-	exec.Command("git", "config", "user.email", "test@example.com").Dir = tmpDir
-	exec.Command("git", "config", "user.name", "Test User").Dir = tmpDir
+	for _, args := range [][]string{
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if err := cmd.Run(); err != nil {
+			os.RemoveAll(tmpDir)
+			t.Fatalf("failed to configure git repo: %v", err)
+		}
+	}
 
 	// Change to test directory
 	oldDir, _ := os.Getwd()
@@ -153,6 +163,38 @@ func TestGetDiffAll(t *testing.T) {
 	}
 }
 
+func TestGetDiffRange(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	// Create two commits to diff between
+	testFile := "test.txt"
+	if err := os.WriteFile(testFile, []byte("initial"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	exec.Command("git", "add", testFile).Run()
+	exec.Command("git", "commit", "-m", "initial").Run()
+
+	if err := os.WriteFile(testFile, []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	exec.Command("git", "commit", "-am", "change").Run()
+
+	diff, err := client.GetDiffRange("HEAD~1..HEAD")
+	if err != nil {
+		t.Fatalf("GetDiffRange() error = %v", err)
+	}
+	if diff == "" {
+		t.Error("GetDiffRange() returned empty diff for changed range")
+	}
+
+	if _, err := client.GetDiffRange("--output=/tmp/x"); err == nil {
+		t.Error("GetDiffRange() should reject option-like ranges")
+	}
+}
+
 func TestCommit(t *testing.T) {
 	_, cleanup := setupTestRepo(t)
 	defer cleanup()
@@ -264,3 +306,202 @@ func TestGetBranch(t *testing.T) {
 		t.Errorf("GetBranch() = %v, want master or main", branch)
 	}
 }
+
+func TestHooksDir(t *testing.T) {
+	tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	dir, err := client.HooksDir()
+	if err != nil {
+		t.Fatalf("HooksDir() error = %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(tmpDir)
+	if dir != filepath.Join(resolved, ".git", "hooks") && dir != filepath.Join(tmpDir, ".git", "hooks") {
+		t.Errorf("HooksDir() = %v, want .git/hooks in the repository", dir)
+	}
+
+	// core.hooksPath is relative to the top of the working tree
+	exec.Command("git", "config", "core.hooksPath", ".githooks").Run()
+	dir, err = client.HooksDir()
+	if err != nil {
+		t.Fatalf("HooksDir() error = %v", err)
+	}
+	if filepath.Base(dir) != ".githooks" || !filepath.IsAbs(dir) {
+		t.Errorf("HooksDir() = %v, want absolute .githooks path", dir)
+	}
+}
+
+func TestApplyCachedAndRestore(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	os.WriteFile("a.txt", []byte("one\n"), 0644)
+	exec.Command("git", "add", "a.txt").Run()
+	if err := client.Commit("test: add a"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	head, err := client.GetHead()
+	if err != nil {
+		t.Fatalf("GetHead() error = %v", err)
+	}
+	tree, err := client.WriteTree()
+	if err != nil {
+		t.Fatalf("WriteTree() error = %v", err)
+	}
+
+	os.WriteFile("a.txt", []byte("two\n"), 0644)
+	patch, err := client.GetPatchAll()
+	if err != nil || patch == "" {
+		t.Fatalf("GetPatchAll() = %q, %v", patch, err)
+	}
+
+	if err := client.ApplyCached(patch); err != nil {
+		t.Fatalf("ApplyCached() error = %v", err)
+	}
+	if !client.HasStagedChanges() {
+		t.Fatal("ApplyCached() should stage the patch")
+	}
+	if err := client.ApplyCached(patch); err == nil {
+		t.Error("ApplyCached() should fail for a patch that no longer applies")
+	}
+
+	if err := client.Commit("test: change a"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := client.ResetSoft(head); err != nil {
+		t.Fatalf("ResetSoft() error = %v", err)
+	}
+	if err := client.ReadTree(tree); err != nil {
+		t.Fatalf("ReadTree() error = %v", err)
+	}
+	if current, _ := client.GetHead(); current != head || client.HasStagedChanges() {
+		t.Error("ResetSoft() and ReadTree() should restore HEAD and the index")
+	}
+	if data, _ := os.ReadFile("a.txt"); string(data) != "two\n" {
+		t.Errorf("working tree changed: %q", data)
+	}
+}
+
+func TestGetLog(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	if _, err := client.GetLog(10); err == nil {
+		t.Error("GetLog() should fail without commits")
+	}
+
+	for i, message := range []string{"feat: first", "fix: second\n\nWith a body."} {
+		os.WriteFile("f.txt", []byte{byte('a' + i)}, 0644)
+		exec.Command("git", "add", "f.txt").Run()
+		if err := client.Commit(message); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+	}
+
+	messages, err := client.GetLog(10)
+	if err != nil {
+		t.Fatalf("GetLog() error = %v", err)
+	}
+	if len(messages) != 2 || messages[0] != "fix: second\n\nWith a body." || messages[1] != "feat: first" {
+		t.Errorf("GetLog() = %q", messages)
+	}
+
+	if messages, _ := client.GetLog(1); len(messages) != 1 {
+		t.Errorf("GetLog(1) returned %d messages", len(messages))
+	}
+}
+
+func TestTopLevel(t *testing.T) {
+	tmpDir, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	os.MkdirAll("sub/dir", 0755)
+	os.Chdir("sub/dir")
+
+	root, err := NewClient().TopLevel()
+	if err != nil {
+		t.Fatalf("TopLevel() error = %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(tmpDir)
+	if root != resolved && root != tmpDir {
+		t.Errorf("TopLevel() = %v, want %v", root, tmpDir)
+	}
+}
+
+func TestGetLogRangeAndDefaultBranch(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	if _, err := client.DefaultBranch(); err == nil {
+		t.Error("DefaultBranch() should fail without branches")
+	}
+
+	os.WriteFile("f.txt", []byte("a"), 0644)
+	exec.Command("git", "add", "f.txt").Run()
+	client.Commit("feat: base")
+	base, _ := client.GetBranch()
+	exec.Command("git", "checkout", "-q", "-b", "topic").Run()
+	os.WriteFile("f.txt", []byte("b"), 0644)
+	exec.Command("git", "add", "f.txt").Run()
+	client.Commit("fix: on topic")
+
+	branch, err := client.DefaultBranch()
+	if err != nil || branch != base {
+		t.Errorf("DefaultBranch() = %q, %v, want %q", branch, err, base)
+	}
+
+	messages, err := client.GetLogRange(base + "..HEAD")
+	if err != nil {
+		t.Fatalf("GetLogRange() error = %v", err)
+	}
+	if len(messages) != 1 || messages[0] != "fix: on topic" {
+		t.Errorf("GetLogRange() = %q", messages)
+	}
+
+	if _, err := client.GetLogRange("--all"); err == nil {
+		t.Error("GetLogRange() should reject option-like ranges")
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	_, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	client := NewClient()
+
+	os.WriteFile("f.txt", []byte("a"), 0644)
+	exec.Command("git", "add", "f.txt").Run()
+	client.Commit("feat: first")
+
+	if _, err := client.LatestTag("HEAD"); err == nil {
+		t.Error("LatestTag() should fail without tags")
+	}
+
+	exec.Command("git", "tag", "v0.1.0").Run()
+	os.WriteFile("f.txt", []byte("b"), 0644)
+	exec.Command("git", "add", "f.txt").Run()
+	client.Commit("fix: second")
+
+	if tag, err := client.LatestTag("HEAD"); err != nil || tag != "v0.1.0" {
+		t.Errorf("LatestTag() = %q, %v, want v0.1.0", tag, err)
+	}
+
+	// A release tag on rev itself yields the release before it
+	exec.Command("git", "tag", "v0.2.0").Run()
+	for _, rev := range []string{"HEAD", "v0.2.0"} {
+		if tag, err := client.LatestTag(rev); err != nil || tag != "v0.1.0" {
+			t.Errorf("LatestTag(%s) = %q, %v, want v0.1.0", rev, tag, err)
+		}
+	}
+	if _, err := client.LatestTag("v0.1.0"); err == nil {
+		t.Error("LatestTag(v0.1.0) should fail without an earlier tag")
+	}
+}
@@ -0,0 +1,226 @@
+package history
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+const (
+	// messageOverhead approximates the tokens added by the chat format around each message
+	messageOverhead = 4
+
+	// SummaryPrefix marks the system message that carries a summary of dropped turns
+	SummaryPrefix = "Summary of the earlier conversation:\n"
+)
+
+// Summarizer condenses older messages into a short summary
+type Summarizer func(ctx context.Context, messages []ai.Message) (string, error)
+
+// Manager keeps a conversation within a token budget
+type Manager struct {
+	budget    int
+	reserve   int
+	summarize Summarizer
+}
+
+// Result describes the outcome of fitting a conversation into the budget
+type Result struct {
+	Messages     []ai.Message
+	Dropped      int
+	Summarized   bool
+	SummaryError error
+	Tokens       int
+}
+
+// NewManager creates a manager for a context window of budget tokens
+// reserve tokens are kept free for the reply, and summarize is optional
+func NewManager(budget, reserve int, summarize Summarizer) *Manager {
+	return &Manager{
+		budget:    budget,
+		reserve:   reserve,
+		summarize: summarize,
+	}
+}
+
+// Available returns the number of tokens the prompt messages may use
+func (m *Manager) Available() int {
+	return m.budget - m.reserve
+}
+
+// EstimateMessages returns the approximate token count of messages
+func EstimateMessages(messages []ai.Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += messageOverhead + tokens.Estimate(msg.Content)
+	}
+	return total
+}
+
+// IsSummary reports whether msg is a summary inserted by the manager
+func IsSummary(msg ai.Message) bool {
+	return msg.Role == "system" && strings.HasPrefix(msg.Content, SummaryPrefix)
+}
+
+// Fit trims the oldest turns until the conversation fits the budget
+// The leading system prompt and the latest message are always kept
+// When a summarizer is set, dropped turns are replaced by a generated summary
+func (m *Manager) Fit(ctx context.Context, messages []ai.Message) Result {
+	if m.budget <= 0 || EstimateMessages(messages) <= m.Available() {
+		return Result{Messages: messages, Tokens: EstimateMessages(messages)}
+	}
+
+	// Split into the system prompt, a previous summary and the conversation turns
+	var head []ai.Message
+	turns := messages
+	if len(turns) > 0 && turns[0].Role == "system" && !IsSummary(turns[0]) {
+		head, turns = turns[:1], turns[1:]
+	}
+	var previous []ai.Message
+	if len(turns) > 0 && IsSummary(turns[0]) {
+		previous, turns = turns[:1], turns[1:]
+	}
+
+	// When summarizing, leave room for the summary itself; otherwise the previous one is kept as it is
+	available := m.Available() - EstimateMessages(head)
+	if m.summarize != nil {
+		available -= m.summaryAllowance()
+	} else {
+		available -= EstimateMessages(previous)
+	}
+
+	cut := m.cutIndex(turns, available)
+	kept := turns[cut:]
+
+	result := Result{Dropped: cut}
+	fitted := append([]ai.Message(nil), head...)
+
+	// Only newly cut turns are summarized, together with the previous summary they extend
+	summary := previous
+	if m.summarize != nil && cut > 0 {
+		dropped := append(append([]ai.Message(nil), previous...), turns[:cut]...)
+		text, err := m.summarize(ctx, dropped)
+		switch {
+		case err != nil:
+			result.SummaryError = err
+		case strings.TrimSpace(text) != "":
+			summary = []ai.Message{{Role: "system", Content: SummaryPrefix + strings.TrimSpace(text)}}
+			result.Summarized = true
+			result.Dropped += len(previous)
+		}
+	}
+
+	fitted = append(fitted, summary...)
+	fitted = append(fitted, kept...)
+
+	// A summary longer than its allowance is paid for with more old turns
+	for EstimateMessages(fitted) > m.Available() {
+		idx := len(head) + len(summary)
+		if idx >= len(fitted)-1 {
+			break
+		}
+		fitted = append(fitted[:idx], fitted[idx+1:]...)
+		result.Dropped++
+	}
+
+	result.Messages = fitted
+	result.Tokens = EstimateMessages(fitted)
+	return result
+}
+
+// cutIndex returns the index of the first turn to keep so that the kept turns fit in available
+func (m *Manager) cutIndex(turns []ai.Message, available int) int {
+	if len(turns) == 0 {
+		return 0
+	}
+
+	// Walk backwards from the newest turn, always keeping the latest message
+	used := EstimateMessages(turns[len(turns)-1:])
+	cut := len(turns) - 1
+	for cut > 0 {
+		cost := EstimateMessages(turns[cut-1 : cut])
+		if used+cost > available {
+			break
+		}
+		used += cost
+		cut--
+	}
+
+	// Never start the kept history with an orphaned assistant reply
+	for cut < len(turns)-1 && turns[cut].Role != "user" {
+		cut++
+	}
+
+	return cut
+}
+
+// summaryAllowance is the token budget set aside for a generated summary
+func (m *Manager) summaryAllowance() int {
+	allowance := m.Available() / 8
+	if allowance > 1000 {
+		allowance = 1000
+	}
+	return allowance
+}
+
+// Window returns the messages to send for transcript when the turns before start are condensed into summary
+// The leading system prompt is always included, and start never skips the latest message
+func Window(transcript []ai.Message, summary string, start int) []ai.Message {
+	var head []ai.Message
+	if len(transcript) > 0 && transcript[0].Role == "system" && !IsSummary(transcript[0]) {
+		head = transcript[:1]
+	}
+	start = max(min(start, len(transcript)-1), len(head))
+
+	window := append([]ai.Message(nil), head...)
+	if summary != "" {
+		window = append(window, ai.Message{Role: "system", Content: SummaryPrefix + summary})
+	}
+	return append(window, transcript[start:]...)
+}
+
+// Summary returns the text of the summary in the fitted messages, or "" if there is none
+func (r Result) Summary() string {
+	for _, msg := range r.Messages {
+		if IsSummary(msg) {
+			return strings.TrimPrefix(msg.Content, SummaryPrefix)
+		}
+	}
+	return ""
+}
+
+// Kept returns how many conversation turns the fitted messages still contain
+func (r Result) Kept() int {
+	kept := 0
+	for i, msg := range r.Messages {
+		if IsSummary(msg) || (i == 0 && msg.Role == "system") {
+			continue
+		}
+		kept++
+	}
+	return kept
+}
+
+// Transcript renders messages as plain text for summarization
+func Transcript(messages []ai.Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		switch {
+		case IsSummary(msg):
+			b.WriteString("Earlier summary: ")
+			b.WriteString(strings.TrimPrefix(msg.Content, SummaryPrefix))
+		case msg.Role == "user":
+			b.WriteString("User: ")
+			b.WriteString(msg.Content)
+		case msg.Role == "assistant":
+			b.WriteString("Assistant: ")
+			b.WriteString(msg.Content)
+		default:
+			continue
+		}
+		b.WriteString("\n\n")
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,273 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+)
+
+// conversation builds a system prompt followed by n question/answer pairs of roughly size tokens each
+func conversation(n, size int) []ai.Message {
+	text := strings.Repeat("word", size)
+	messages := []ai.Message{{Role: "system", Content: "system prompt"}}
+	for i := 0; i < n; i++ {
+		messages = append(messages,
+			ai.Message{Role: "user", Content: text},
+			ai.Message{Role: "assistant", Content: text},
+		)
+	}
+	return messages
+}
+
+func TestEstimateMessages(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "user", Content: "abcd"},
+		{Role: "assistant", Content: ""},
+	}
+
+	if got := EstimateMessages(messages); got != 2*messageOverhead+1 {
+		t.Errorf("EstimateMessages() = %d, want %d", got, 2*messageOverhead+1)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	m := NewManager(1000, 300, nil)
+	if got := m.Available(); got != 700 {
+		t.Errorf("Available() = %d, want 700", got)
+	}
+}
+
+func TestFit_UnderBudget(t *testing.T) {
+	messages := conversation(2, 10)
+	m := NewManager(10000, 100, nil)
+
+	result := m.Fit(context.Background(), messages)
+
+	if len(result.Messages) != len(messages) {
+		t.Errorf("Fit() returned %d messages, want %d", len(result.Messages), len(messages))
+	}
+	if result.Dropped != 0 {
+		t.Errorf("Fit() Dropped = %d, want 0", result.Dropped)
+	}
+}
+
+func TestFit_DisabledBudget(t *testing.T) {
+	messages := conversation(50, 100)
+	m := NewManager(0, 0, nil)
+
+	result := m.Fit(context.Background(), messages)
+
+	if len(result.Messages) != len(messages) {
+		t.Errorf("Fit() with zero budget should keep all messages")
+	}
+}
+
+func TestFit_DropsOldestTurns(t *testing.T) {
+	// Each message costs 14 tokens (10 content + 4 overhead)
+	messages := append(conversation(5, 10), ai.Message{Role: "user", Content: "latest question"})
+	m := NewManager(100, 20, nil)
+
+	result := m.Fit(context.Background(), messages)
+
+	if result.Messages[0].Content != "system prompt" {
+		t.Error("Fit() must keep the system prompt first")
+	}
+	if last := result.Messages[len(result.Messages)-1]; last.Content != "latest question" {
+		t.Errorf("Fit() last message = %q, want latest question", last.Content)
+	}
+	if result.Dropped == 0 {
+		t.Error("Fit() should drop turns when over budget")
+	}
+	if result.Tokens > m.Available() {
+		t.Errorf("Fit() Tokens = %d, want <= %d", result.Tokens, m.Available())
+	}
+	if result.Messages[1].Role != "user" {
+		t.Errorf("Fit() first kept turn role = %q, want user", result.Messages[1].Role)
+	}
+}
+
+func TestFit_KeepsLatestMessageEvenIfTooLarge(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "user", Content: "old"},
+		{Role: "assistant", Content: "old answer"},
+		{Role: "user", Content: strings.Repeat("huge", 500)},
+	}
+	m := NewManager(100, 10, nil)
+
+	result := m.Fit(context.Background(), messages)
+
+	if len(result.Messages) != 2 {
+		t.Fatalf("Fit() returned %d messages, want system prompt and latest message", len(result.Messages))
+	}
+	if result.Messages[1].Role != "user" {
+		t.Errorf("Fit() kept %q, want the latest user message", result.Messages[1].Role)
+	}
+}
+
+func TestFit_Summarizes(t *testing.T) {
+	messages := append(conversation(10, 10), ai.Message{Role: "user", Content: "latest question"})
+
+	var summarized []ai.Message
+	summarize := func(ctx context.Context, dropped []ai.Message) (string, error) {
+		summarized = dropped
+		return "they talked about words", nil
+	}
+	m := NewManager(200, 20, summarize)
+
+	result := m.Fit(context.Background(), messages)
+
+	if !result.Summarized {
+		t.Fatal("Fit() Summarized = false, want true")
+	}
+	if len(summarized) != result.Dropped {
+		t.Errorf("summarizer received %d messages, want %d dropped", len(summarized), result.Dropped)
+	}
+	if !IsSummary(result.Messages[1]) {
+		t.Errorf("Fit() second message = %+v, want summary", result.Messages[1])
+	}
+	if !strings.Contains(result.Messages[1].Content, "they talked about words") {
+		t.Error("Fit() summary message does not contain generated summary")
+	}
+	if result.Tokens > m.Available() {
+		t.Errorf("Fit() Tokens = %d, want <= %d", result.Tokens, m.Available())
+	}
+}
+
+func TestFit_ResummarizesPreviousSummary(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "system", Content: SummaryPrefix + "earlier summary"},
+	}
+	messages = append(messages, conversation(10, 10)[1:]...)
+	messages = append(messages, ai.Message{Role: "user", Content: "latest question"})
+
+	var received []ai.Message
+	summarize := func(ctx context.Context, dropped []ai.Message) (string, error) {
+		received = dropped
+		return "merged summary", nil
+	}
+	m := NewManager(200, 20, summarize)
+
+	result := m.Fit(context.Background(), messages)
+
+	if len(received) == 0 || !IsSummary(received[0]) {
+		t.Error("Fit() should pass the previous summary to the summarizer")
+	}
+
+	summaries := 0
+	for _, msg := range result.Messages {
+		if IsSummary(msg) {
+			summaries++
+		}
+	}
+	if summaries != 1 {
+		t.Errorf("Fit() produced %d summary messages, want 1", summaries)
+	}
+	if result.Dropped != len(received) {
+		t.Errorf("Fit() Dropped = %d, want %d including the previous summary", result.Dropped, len(received))
+	}
+}
+
+func TestFit_KeepsPreviousSummaryWhenNothingIsCut(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "system", Content: SummaryPrefix + "earlier summary"},
+		{Role: "user", Content: strings.Repeat("huge", 500)},
+	}
+
+	calls := 0
+	summarize := func(ctx context.Context, dropped []ai.Message) (string, error) {
+		calls++
+		return "summary of a summary", nil
+	}
+	m := NewManager(200, 20, summarize)
+
+	result := m.Fit(context.Background(), messages)
+
+	if calls != 0 {
+		t.Errorf("summarizer called %d times, want 0 when no turn was cut", calls)
+	}
+	if result.Summarized || result.Dropped != 0 {
+		t.Errorf("Fit() Summarized = %v, Dropped = %d, want false and 0", result.Summarized, result.Dropped)
+	}
+	if len(result.Messages) != 3 || result.Messages[1].Content != messages[1].Content {
+		t.Errorf("Fit() = %+v, want the previous summary kept", result.Messages)
+	}
+}
+
+func TestFit_SummaryErrorFallsBackToDropping(t *testing.T) {
+	messages := append(conversation(10, 10), ai.Message{Role: "user", Content: "latest question"})
+	wantErr := errors.New("summary failed")
+	summarize := func(ctx context.Context, dropped []ai.Message) (string, error) {
+		return "", wantErr
+	}
+	m := NewManager(200, 20, summarize)
+
+	result := m.Fit(context.Background(), messages)
+
+	if result.Summarized {
+		t.Error("Fit() Summarized = true, want false on summarizer error")
+	}
+	if !errors.Is(result.SummaryError, wantErr) {
+		t.Errorf("Fit() SummaryError = %v, want %v", result.SummaryError, wantErr)
+	}
+	if result.Tokens > m.Available() {
+		t.Errorf("Fit() Tokens = %d, want <= %d", result.Tokens, m.Available())
+	}
+}
+
+func TestWindow(t *testing.T) {
+	transcript := []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+	}
+
+	window := Window(transcript, "they said hi", 3)
+	if len(window) != 3 || window[0].Content != "system prompt" || !IsSummary(window[1]) || window[2].Content != "q2" {
+		t.Errorf("Window() = %+v, want system prompt, summary and q2", window)
+	}
+
+	// A start past the end still sends the latest message, and without a summary nothing is inserted
+	window = Window(transcript, "", 10)
+	if len(window) != 2 || window[1].Content != "q2" {
+		t.Errorf("Window() = %+v, want system prompt and q2", window)
+	}
+	if got := Window(transcript, "", 0); len(got) != len(transcript) {
+		t.Errorf("Window() returned %d messages, want the whole transcript", len(got))
+	}
+}
+
+func TestResult_SummaryAndKept(t *testing.T) {
+	result := Result{Messages: []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "system", Content: SummaryPrefix + "earlier"},
+		{Role: "user", Content: "q"},
+		{Role: "assistant", Content: "a"},
+	}}
+
+	if result.Summary() != "earlier" || result.Kept() != 2 {
+		t.Errorf("Summary() = %q, Kept() = %d, want earlier and 2", result.Summary(), result.Kept())
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "system", Content: "system prompt"},
+		{Role: "system", Content: SummaryPrefix + "talked about Go"},
+		{Role: "user", Content: "What about channels?"},
+		{Role: "assistant", Content: "They pass values."},
+	}
+
+	transcript := Transcript(messages)
+	expected := "Earlier summary: talked about Go\n\nUser: What about channels?\n\nAssistant: They pass values."
+
+	if transcript != expected {
+		t.Errorf("Transcript() = %q, want %q", transcript, expected)
+	}
+}
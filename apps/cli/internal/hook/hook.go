@@ -0,0 +1,149 @@
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Name is the git hook zik installs
+	Name = "prepare-commit-msg"
+
+	// marker identifies hook scripts written by zik
+	marker = "# Installed by zik"
+
+	// chainedSuffix is appended to a pre-existing hook that the zik hook runs first
+	chainedSuffix = ".pre-zik"
+)
+
+// ErrForeignHook is returned when a hook exists that zik did not install and cannot chain
+var ErrForeignHook = errors.New("a prepare-commit-msg hook not managed by zik already exists")
+
+// Status describes the prepare-commit-msg hook in a hooks directory
+type Status struct {
+	Path      string // Path of the hook script
+	Installed bool   // The hook is the zik hook
+	Foreign   bool   // A hook exists but was not installed by zik
+	Chained   string // Path of a previous hook run before zik, if any
+}
+
+// Script returns the hook script that calls zik at zikPath
+// The previous hook, if any, runs first and can still abort the commit;
+// zik failures never block committing
+func Script(zikPath string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s; remove with "zik hook uninstall"
+# Pre-fills the commit message with a generated one for plain "git commit".
+
+hook_dir=$(dirname "$0")
+if [ -x "$hook_dir/%s%s" ]; then
+	"$hook_dir/%s%s" "$@" || exit $?
+fi
+
+ZIK=%s
+[ -x "$ZIK" ] || ZIK=zik
+"$ZIK" commit --hook "$@" </dev/null || true
+`, marker, Name, chainedSuffix, Name, chainedSuffix, shellQuote(zikPath))
+}
+
+// Inspect reports the state of the hook in dir
+func Inspect(dir string) (Status, error) {
+	status := Status{Path: filepath.Join(dir, Name)}
+
+	data, err := os.ReadFile(status.Path)
+	switch {
+	case os.IsNotExist(err):
+		return status, nil
+	case err != nil:
+		return status, err
+	}
+
+	if strings.Contains(string(data), marker) {
+		status.Installed = true
+	} else {
+		status.Foreign = true
+	}
+
+	chained := status.Path + chainedSuffix
+	if _, err := os.Stat(chained); err == nil {
+		status.Chained = chained
+	}
+
+	return status, nil
+}
+
+// Install writes the zik hook into dir
+// An existing foreign hook is kept and chained when chain is true, otherwise ErrForeignHook is returned
+func Install(dir, zikPath string, chain bool) (Status, error) {
+	status, err := Inspect(dir)
+	if err != nil {
+		return status, err
+	}
+
+	if status.Foreign {
+		if !chain {
+			return status, ErrForeignHook
+		}
+		if status.Chained != "" {
+			return status, fmt.Errorf("cannot chain existing hook: %s already exists", status.Chained)
+		}
+		if err := os.Rename(status.Path, status.Path+chainedSuffix); err != nil {
+			return status, fmt.Errorf("failed to keep existing hook: %w", err)
+		}
+		status.Chained = status.Path + chainedSuffix
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return status, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(status.Path, []byte(Script(zikPath)), 0755); err != nil {
+		return status, fmt.Errorf("failed to write hook: %w", err)
+	}
+
+	status.Installed, status.Foreign = true, false
+	return status, nil
+}
+
+// Uninstall removes the zik hook from dir and restores a chained hook
+func Uninstall(dir string) (Status, error) {
+	status, err := Inspect(dir)
+	if err != nil {
+		return status, err
+	}
+
+	if status.Foreign {
+		return status, ErrForeignHook
+	}
+	if !status.Installed {
+		return status, nil
+	}
+
+	if err := os.Remove(status.Path); err != nil {
+		return status, fmt.Errorf("failed to remove hook: %w", err)
+	}
+	status.Installed = false
+
+	if status.Chained != "" {
+		if err := os.Rename(status.Chained, status.Path); err != nil {
+			return status, fmt.Errorf("failed to restore previous hook: %w", err)
+		}
+		status.Chained, status.Foreign = "", true
+	}
+
+	return status, nil
+}
+
+// ShouldGenerate reports whether a message should be generated for a prepare-commit-msg source
+// Only plain "git commit" qualifies: messages given with -m/-F or templates, merges,
+// squashes and amends (source "commit") are left alone
+func ShouldGenerate(source string) bool {
+	return source == ""
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
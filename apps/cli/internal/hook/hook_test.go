@@ -0,0 +1,148 @@
+package hook
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeHook(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+}
+
+func TestInstall_Fresh(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	status, err := Install(dir, "/usr/local/bin/zik", true)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !status.Installed || status.Chained != "" {
+		t.Errorf("Install() status = %+v", status)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, Name))
+	if err != nil {
+		t.Fatalf("hook not written: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Error("hook is not executable")
+	}
+
+	// Installing again simply rewrites the zik hook
+	if _, err := Install(dir, "/usr/local/bin/zik", false); err != nil {
+		t.Errorf("reinstall error = %v", err)
+	}
+}
+
+func TestInstall_ChainsForeignHook(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, filepath.Join(dir, Name), "#!/bin/sh\necho custom\n")
+
+	if _, err := Install(dir, "zik", false); !errors.Is(err, ErrForeignHook) {
+		t.Fatalf("Install(chain=false) error = %v, want ErrForeignHook", err)
+	}
+
+	status, err := Install(dir, "zik", true)
+	if err != nil {
+		t.Fatalf("Install(chain=true) error = %v", err)
+	}
+	if status.Chained != filepath.Join(dir, Name+chainedSuffix) {
+		t.Errorf("Chained = %q", status.Chained)
+	}
+
+	data, _ := os.ReadFile(status.Chained)
+	if !strings.Contains(string(data), "echo custom") {
+		t.Error("previous hook was not preserved")
+	}
+}
+
+func TestUninstall_RestoresChainedHook(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, filepath.Join(dir, Name), "#!/bin/sh\necho custom\n")
+
+	if _, err := Install(dir, "zik", true); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	status, err := Uninstall(dir)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if status.Installed || !status.Foreign {
+		t.Errorf("Uninstall() status = %+v, want the restored foreign hook", status)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, Name))
+	if !strings.Contains(string(data), "echo custom") {
+		t.Error("Uninstall() did not restore the previous hook")
+	}
+}
+
+func TestUninstall_LeavesForeignHook(t *testing.T) {
+	dir := t.TempDir()
+	writeHook(t, filepath.Join(dir, Name), "#!/bin/sh\n")
+
+	if _, err := Uninstall(dir); !errors.Is(err, ErrForeignHook) {
+		t.Errorf("Uninstall() error = %v, want ErrForeignHook", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, Name)); err != nil {
+		t.Error("Uninstall() removed a foreign hook")
+	}
+}
+
+func TestInspect_Missing(t *testing.T) {
+	status, err := Inspect(t.TempDir())
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if status.Installed || status.Foreign {
+		t.Errorf("Inspect() = %+v, want nothing installed", status)
+	}
+}
+
+func TestScript(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// Run the hook with a fake zik that records its arguments, chained after a previous hook
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	fakeZik := filepath.Join(dir, "zik's")
+	writeHook(t, fakeZik, "#!/bin/sh\necho \"zik $*\" >> \""+log+"\"\n")
+	writeHook(t, filepath.Join(dir, Name+chainedSuffix), "#!/bin/sh\necho \"previous $*\" >> \""+log+"\"\n")
+	writeHook(t, filepath.Join(dir, Name), Script(fakeZik))
+
+	cmd := exec.Command(filepath.Join(dir, Name), "MSG", "message")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, out)
+	}
+
+	data, _ := os.ReadFile(log)
+	expected := "previous MSG message\nzik commit --hook MSG message\n"
+	if string(data) != expected {
+		t.Errorf("hook calls = %q, want %q", string(data), expected)
+	}
+}
+
+func TestShouldGenerate(t *testing.T) {
+	for source, want := range map[string]bool{
+		"":         true,
+		"message":  false,
+		"template": false,
+		"merge":    false,
+		"squash":   false,
+		"commit":   false,
+	} {
+		if got := ShouldGenerate(source); got != want {
+			t.Errorf("ShouldGenerate(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
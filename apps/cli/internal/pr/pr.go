@@ -0,0 +1,110 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// headingPattern matches Markdown headings
+	headingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+
+	// titleLabelPattern matches heading marks and labels some models put before the title
+	titleLabelPattern = regexp.MustCompile(`(?i)^#*\s*((\*\*)?(pr |pull request )?title(\*\*)?:(\*\*)?\s*)?`)
+)
+
+// Template is a pull request template found in a repository
+type Template struct {
+	Path    string
+	Content string
+}
+
+// FindTemplate looks for a pull request template in the .github directory of root
+// Names are matched case-insensitively, and the first file of a PULL_REQUEST_TEMPLATE
+// directory is used when there is no single template
+func FindTemplate(root string) (Template, bool, error) {
+	dir := filepath.Join(root, ".github")
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return Template{}, false, nil
+	}
+	if err != nil {
+		return Template{}, false, err
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		switch {
+		case !entry.IsDir() && (name == "pull_request_template.md" || name == "pull_request_template.txt"):
+			candidates = append([]string{filepath.Join(dir, entry.Name())}, candidates...)
+		case entry.IsDir() && name == "pull_request_template":
+			files, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return Template{}, false, err
+			}
+			var names []string
+			for _, f := range files {
+				if !f.IsDir() && strings.HasSuffix(strings.ToLower(f.Name()), ".md") {
+					names = append(names, f.Name())
+				}
+			}
+			slices.Sort(names)
+			if len(names) > 0 {
+				candidates = append(candidates, filepath.Join(dir, entry.Name(), names[0]))
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Template{}, false, nil
+	}
+
+	data, err := os.ReadFile(candidates[0])
+	if err != nil {
+		return Template{}, false, err
+	}
+	return Template{Path: candidates[0], Content: string(data)}, true, nil
+}
+
+// Headings returns the Markdown headings of a template in order
+func Headings(markdown string) []string {
+	var headings []string
+	inFence := false
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil && !inFence {
+			headings = append(headings, m[1])
+		}
+	}
+	return headings
+}
+
+// Parse splits a generated pull request into its title and Markdown body
+// The title is the first non-empty line, without a "Title:" label or heading marks;
+// a fence wrapping the whole reply is removed
+func Parse(text string) (string, string) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+
+	lines := strings.Split(text, "\n")
+	if strings.HasPrefix(lines[0], "```") && len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "```" {
+		text = strings.TrimSpace(strings.Join(lines[1:len(lines)-1], "\n"))
+	}
+
+	title, body, _ := strings.Cut(text, "\n")
+	title = strings.TrimSpace(titleLabelPattern.ReplaceAllString(strings.TrimSpace(title), ""))
+	title = strings.Trim(title, "`\"*")
+	return title, strings.TrimSpace(body)
+}
+
+// Format joins a title and body as written to stdout or --output: the title, a blank line, then the body
+func Format(title, body string) string {
+	return title + "\n\n" + body + "\n"
+}
@@ -0,0 +1,76 @@
+package pr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindTemplate(t *testing.T) {
+	root := t.TempDir()
+
+	if _, found, err := FindTemplate(root); found || err != nil {
+		t.Fatalf("FindTemplate() without .github = %v, %v", found, err)
+	}
+
+	dir := filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "feature.md"), []byte("## Feature"), 0644)
+	os.WriteFile(filepath.Join(dir, "bugfix.md"), []byte("## Bug"), 0644)
+
+	tmpl, found, err := FindTemplate(root)
+	if err != nil || !found || tmpl.Content != "## Bug" {
+		t.Fatalf("FindTemplate() = %+v, %v, %v, want the first template of the directory", tmpl, found, err)
+	}
+
+	single := filepath.Join(root, ".github", "Pull_Request_Template.md")
+	os.WriteFile(single, []byte("## What"), 0644)
+
+	tmpl, found, err = FindTemplate(root)
+	if err != nil || !found || tmpl.Path != single {
+		t.Errorf("FindTemplate() = %+v, %v, %v, want the single template first", tmpl, found, err)
+	}
+}
+
+func TestHeadings(t *testing.T) {
+	template := `<!-- Describe your change -->
+## What does this PR do?
+
+### How was it tested? ###
+
+` + "```" + `
+# not a heading
+` + "```" + `
+## Checklist
+- [ ] Tests added
+`
+	want := []string{"What does this PR do?", "How was it tested?", "Checklist"}
+	if got := Headings(template); !reflect.DeepEqual(got, want) {
+		t.Errorf("Headings() = %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantTitle string
+		wantBody  string
+	}{
+		{name: "plain", text: "Add login\n\n## Summary\nAdds login.", wantTitle: "Add login", wantBody: "## Summary\nAdds login."},
+		{name: "label", text: "**Title:** Add login\n\n## Summary\nx", wantTitle: "Add login", wantBody: "## Summary\nx"},
+		{name: "heading", text: "# PR title: Add login\n## Summary\nx", wantTitle: "Add login", wantBody: "## Summary\nx"},
+		{name: "fenced", text: "```markdown\nAdd login\n\nBody\n```", wantTitle: "Add login", wantBody: "Body"},
+		{name: "title word kept", text: "Titles are trimmed\n\nBody", wantTitle: "Titles are trimmed", wantBody: "Body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := Parse(tt.text)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("Parse() = %q, %q, want %q, %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
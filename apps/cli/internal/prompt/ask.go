@@ -1,11 +1,7 @@
 package prompt
 
-// AskSystemPrompt generates the system prompt for ask command
-// This prompt instructs the AI about supported markdown formatting in CLI
-func AskSystemPrompt() string {
-	return `You are a helpful AI assistant answering questions in a terminal environment.
-
-CRITICAL FORMATTING RULES:
+// terminalFormattingRules describes the markdown subset supported by the CLI renderer
+const terminalFormattingRules = `CRITICAL FORMATTING RULES:
 Your responses will be rendered in a CLI terminal with LIMITED markdown support.
 
 SUPPORTED formatting (use these freely):
@@ -28,7 +24,14 @@ UNSUPPORTED formatting (DO NOT USE):
 When you need to show lists or structured information:
 - Use simple line breaks and indentation
 - Use plain text with dashes or numbers as regular text
-- Example: "Option 1: Description\nOption 2: Description"
+- Example: "Option 1: Description\nOption 2: Description"`
+
+// AskSystemPrompt generates the system prompt for ask command
+// This prompt instructs the AI about supported markdown formatting in CLI
+func AskSystemPrompt() string {
+	return `You are a helpful AI assistant answering questions in a terminal environment.
+
+` + terminalFormattingRules + `
 
 Keep responses clear, concise, and well-formatted for terminal display.`
 }
@@ -0,0 +1,77 @@
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// languageByExtension maps file extensions to code fence language tags
+var languageByExtension = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "jsx",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".php":   "php",
+	".swift": "swift",
+	".sh":    "bash",
+	".bash":  "bash",
+	".zsh":   "zsh",
+	".sql":   "sql",
+	".html":  "html",
+	".css":   "css",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".md":    "markdown",
+	".proto": "protobuf",
+	".mod":   "go",
+}
+
+// languageByName maps well-known file names without a useful extension
+var languageByName = map[string]string{
+	"Dockerfile": "dockerfile",
+	"Makefile":   "makefile",
+}
+
+// Language guesses the code fence language tag for a file path
+func Language(path string) string {
+	if lang, ok := languageByName[filepath.Base(path)]; ok {
+		return lang
+	}
+	return languageByExtension[strings.ToLower(filepath.Ext(path))]
+}
+
+// FileBlock formats file contents as a fenced code block labelled with its path
+func FileBlock(path, content string) string {
+	return fencedBlock("File: "+path, Language(path), content)
+}
+
+// StdinBlock formats input piped into the CLI as a fenced block
+func StdinBlock(content string) string {
+	return fencedBlock("Piped input:", "", content)
+}
+
+// fencedBlock wraps content in a code fence preceded by a label line
+func fencedBlock(label, lang, content string) string {
+	// Use a longer fence when the content itself contains fenced blocks
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+
+	return fmt.Sprintf("%s\n%s%s\n%s\n%s", label, fence, lang, strings.TrimRight(content, "\n"), fence)
+}
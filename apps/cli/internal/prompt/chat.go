@@ -0,0 +1,32 @@
+package prompt
+
+import "fmt"
+
+// ChatSystemPrompt generates the system prompt for interactive chat sessions
+func ChatSystemPrompt() string {
+	return `You are a helpful AI assistant having a multi-turn conversation with a developer in a terminal environment.
+Use the previous messages of the conversation as context for follow-up questions.
+
+` + terminalFormattingRules + `
+
+Keep responses clear, concise, and well-formatted for terminal display.`
+}
+
+// ChatSummarySystemPrompt generates the system prompt for condensing old conversation turns
+func ChatSummarySystemPrompt() string {
+	return `You condense conversations between a developer and an AI assistant.
+Write a compact summary of the transcript you are given so the conversation can continue without it.
+
+Rules:
+1. Keep facts, decisions, code identifiers, file names, commands and open questions
+2. Drop greetings, repetition and explanations that were already understood
+3. Write plain text in at most a few short paragraphs
+4. Return ONLY the summary, no preamble`
+}
+
+// ChatSummaryUserPrompt wraps the transcript that should be summarized
+func ChatSummaryUserPrompt(transcript string) string {
+	return fmt.Sprintf(`Summarize the following conversation:
+
+%s`, transcript)
+}
@@ -1,40 +1,117 @@
 package prompt
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/commitmsg"
+)
+
+// CommitOptions controls the shape of generated commit messages
+type CommitOptions struct {
+	Conventional  bool     // Follow the Conventional Commits standard
+	PreferredType string   // Type to prefer when appropriate
+	Body          bool     // Allow a body explaining the motivation
+	Conventions   []string // House conventions learned from the repository history
+	Examples      []string // Recent commit messages from the repository
+	Types         []string // Allowed types, the standard ones when empty
+	Scopes        []string // Allowed scopes, any when empty
+	Areas         []string // Scopes of the changed files, most changed first
+	SubjectLimit  int      // Maximum subject length, 72 when zero
+}
 
 // CommitSystemPrompt generates the system prompt for commit message generation
-func CommitSystemPrompt(conventional bool, preferredType string) string {
-	base := `You are an expert at analyzing code changes and writing clear, concise commit messages.
-Your task is to analyze git diff output and generate a meaningful commit message.`
+func CommitSystemPrompt(opts CommitOptions) string {
+	return `You are an expert at analyzing code changes and writing clear, concise commit messages.
+Your task is to analyze git diff output and generate a meaningful commit message.` + commitMessageRules(opts) + `
 
-	if conventional {
-		base += `
+Return ONLY the commit message, no explanations, code fences or additional text.`
+}
+
+// commitMessageRules describes the format of a commit message
+func commitMessageRules(opts CommitOptions) string {
+	base := ""
+
+	// Offer exactly the types the validator accepts
+	types := strings.Join(commitmsg.DefaultTypes, ", ")
+	if len(opts.Types) > 0 {
+		types = strings.Join(opts.Types, ", ") + " (no other types are allowed)"
+	}
+	scopes := "optional, indicates the area of change"
+	if len(opts.Scopes) > 0 {
+		scopes = "optional, one of " + strings.Join(opts.Scopes, ", ") + " (no other scopes are allowed)"
+	}
+	subjectLimit := opts.SubjectLimit
+	if subjectLimit == 0 {
+		subjectLimit = 72
+	}
+
+	if opts.Conventional {
+		base += fmt.Sprintf(`
 
 Follow the Conventional Commits standard strictly:
 - Format: type(scope): description
-- Types: feat, fix, docs, style, refactor, perf, test, chore
-- Scope: optional, indicates the area of change
+- Types: %s
+- Scope: %s
 - Description: imperative mood, lowercase, no period at end
-
+`, types, scopes)
+		base += `
 Examples:
 - feat(auth): add JWT token validation
 - fix(api): handle null response in user endpoint
 - docs(readme): update installation instructions
 - refactor(parser): simplify token extraction logic`
 
-		if preferredType != "" {
-			base += fmt.Sprintf("\n- Prefer using '%s' type when appropriate", preferredType)
+		if opts.PreferredType != "" {
+			base += fmt.Sprintf("\n- Prefer using '%s' type when appropriate", opts.PreferredType)
+		}
+		if len(opts.Areas) > 0 {
+			base += fmt.Sprintf("\n- The changed files belong to these scopes: %s. Use the main one as the scope", strings.Join(opts.Areas, ", "))
 		}
 	}
 
-	base += `
+	base += fmt.Sprintf(`
 
 Rules:
-1. Keep the message concise (max 72 characters for first line)
+1. Keep the first line (subject) at most %d characters
 2. Focus on WHAT changed and WHY, not HOW
 3. Use imperative mood ("add" not "added" or "adds")
-4. Be specific but brief
-5. Return ONLY the commit message, no explanations or additional text`
+4. Be specific but brief`, subjectLimit)
+
+	if opts.Body {
+		base += `
+
+Message structure:
+- Subject line, then a blank line
+- Optional body: a few sentences or "- " bullets explaining the motivation and notable details.
+  Omit the body for small, self-explanatory changes
+- Optional footers after another blank line, one per line`
+	} else {
+		base += `
+
+Message structure:
+- A single subject line, no body
+- Optional footers after a blank line, one per line`
+	}
+
+	if opts.Conventional {
+		base += `
+- If the change breaks existing behaviour or APIs, add a footer "BREAKING CHANGE: <what breaks and how to migrate>"
+  and mark the subject with "!" after the type or scope, e.g. "feat(api)!: remove v1 endpoints"
+- Use "Refs: <reference>" only for references that appear in the diff; never invent issue numbers`
+	} else {
+		base += `
+- Mention breaking changes explicitly in a "BREAKING CHANGE:" footer`
+	}
+
+	if len(opts.Conventions) > 0 {
+		base += "\n\nThis repository's history shows these conventions; follow them where they differ from the rules above:\n- " +
+			strings.Join(opts.Conventions, "\n- ")
+	}
+	if len(opts.Examples) > 0 {
+		base += "\n\nRecent commit messages from this repository, for style only:\n---\n" +
+			strings.Join(opts.Examples, "\n---\n") + "\n---"
+	}
 
 	return base
 }
@@ -47,3 +124,68 @@ func CommitUserPrompt(diff string) string {
 
 Generate a commit message for these changes.`, diff)
 }
+
+// CommitFileSummarySystemPrompt generates the system prompt for summarizing the files of a large diff
+func CommitFileSummarySystemPrompt() string {
+	return `You summarize the git diff of one or more files so that a commit message can be written later.
+For each file, describe WHAT changed and, when it is evident, WHY, in at most three short sentences.
+Mention renamed or removed functions, types and behaviour changes explicitly.
+Return one line per file in the form "path/to/file: summary", without headings or code.`
+}
+
+// CommitFileSummaryUserPrompt generates the user prompt with the diffs of the files to summarize
+func CommitFileSummaryUserPrompt(diff string) string {
+	return fmt.Sprintf(`Summarize the changes to each file:
+
+%s`, diff)
+}
+
+// CommitSummariesUserPrompt generates the user prompt from per-file summaries of a diff too large to send whole
+func CommitSummariesUserPrompt(summaries string) string {
+	return fmt.Sprintf(`The diff is too large to show in full. These are summaries of the changes per file:
+
+%s
+
+Generate a commit message for these changes.`, summaries)
+}
+
+// CommitSplitSystemPrompt generates the system prompt for splitting changes into several commits
+func CommitSplitSystemPrompt(opts CommitOptions) string {
+	return `You are an expert at organizing code changes into small, focused commits.
+You receive numbered changes from a git diff; each is a whole file or a single hunk of a file.
+Group them into logical, atomic commits and write a commit message for each commit.
+
+Grouping rules:
+1. Every change belongs to exactly one commit
+2. Each commit contains one logical change; hunks of the same file may go to different commits
+3. Order the commits so each builds on the previous ones, e.g. refactors before the features that use them
+4. Do not split a change that only makes sense together, such as a function and its only caller
+
+Each commit message follows these rules:` + commitMessageRules(opts) + `
+
+Respond with JSON only, without code fences or additional text. Separate the subject, body and footers
+of a message with "\n\n" inside the JSON string:
+{"commits": [{"message": "feat(api): add login endpoint", "changes": [1, 4]}, {"message": "docs: describe login", "changes": [2]}]}`
+}
+
+// CommitSplitUserPrompt generates the user prompt with the numbered changes
+func CommitSplitUserPrompt(changes string) string {
+	return fmt.Sprintf(`Split these changes into commits:
+
+%s`, changes)
+}
+
+// CommitRepairPrompt asks for a corrected commit message listing the problems found in the previous one
+func CommitRepairPrompt(problems []string) string {
+	return fmt.Sprintf(`The commit message has these problems:
+- %s
+
+Fix them and return ONLY the corrected commit message.`, strings.Join(problems, "\n- "))
+}
+
+// CommitFeedbackPrompt asks for a revised commit message following the user's feedback
+func CommitFeedbackPrompt(feedback string) string {
+	return fmt.Sprintf(`Revise the commit message according to this feedback: %s
+
+Return ONLY the revised commit message.`, feedback)
+}
@@ -0,0 +1,39 @@
+package prompt
+
+import "fmt"
+
+// ExplainSystemPrompt generates the system prompt for code explanation
+// depth is either "summary" or "line"
+func ExplainSystemPrompt(depth string) string {
+	base := `You are a senior engineer explaining unfamiliar code to a developer who just joined the team.
+Explain what the code does, why it is structured this way and how it fits into the surrounding program.`
+
+	if depth == "line" {
+		base += `
+
+Walk through the code line by line or in small groups of related lines.
+Refer to the line numbers shown at the start of each line, for example "Lines 12-15: ...".
+Point out non-obvious behaviour, edge cases and possible bugs as you go.`
+	} else {
+		base += `
+
+Give a concise high-level summary: purpose, inputs and outputs, key types and functions,
+control flow and any non-obvious behaviour or pitfalls. Do not walk through every line.`
+	}
+
+	return base + `
+
+` + terminalFormattingRules
+}
+
+// ExplainUserPrompt generates the user prompt with the code to explain
+func ExplainUserPrompt(path, language string, start, end, part, parts int, code string) string {
+	location := fmt.Sprintf("%s (lines %d-%d)", path, start, end)
+	if parts > 1 {
+		location += fmt.Sprintf(", part %d of %d", part, parts)
+	}
+
+	return fmt.Sprintf(`Explain the following code from %s:
+
+`+"```%s\n%s\n```", location, language, code)
+}
@@ -0,0 +1,13 @@
+package prompt
+
+import "strings"
+
+// WithInstructions appends the user's configured instructions to a system prompt
+func WithInstructions(system, instructions string) string {
+	instructions = strings.TrimSpace(instructions)
+	if instructions == "" {
+		return system
+	}
+
+	return system + "\n\nAdditional instructions from the user:\n" + instructions
+}
@@ -0,0 +1,58 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PRSystemPrompt generates the system prompt for drafting a pull request
+// With a template, the body follows its headings; otherwise it has summary, changes, testing and risk sections
+func PRSystemPrompt(template string) string {
+	base := `You are an experienced engineer writing the title and description of a pull request.
+You receive the commit messages of the branch and its combined diff.
+
+Rules:
+1. The first line is the title: at most 72 characters, imperative mood, no trailing period, no "Title:" label
+2. After a blank line comes the body in GitHub-flavored Markdown
+3. Describe WHAT changes and WHY for a reviewer who has not seen the code; mention files only when it helps
+4. Base every statement on the commits and the diff; never invent tests, tickets or benchmarks
+5. Return ONLY the title and body, without code fences around them`
+
+	if template != "" {
+		base += fmt.Sprintf(`
+
+The repository has a pull request template. Write the body by filling it in:
+- Keep its headings, in the same order, and write under each one
+- Replace placeholder text and HTML comments with real content
+- Keep checklists, ticking "[x]" only for items the changes clearly satisfy
+- Write "N/A" for sections that do not apply
+
+Template:
+%s`, strings.TrimSpace(template))
+		return base
+	}
+
+	base += `
+
+Structure the body with these sections, in this order:
+- "## Summary": two or three sentences on the purpose of the change
+- "## Changes": "- " bullets of the notable changes, grouped by area
+- "## Testing": how the change was or can be verified; say so when the diff adds no tests
+- "## Risk": what could break, migrations or config changes, and how to roll back`
+	return base
+}
+
+// PRUserPrompt generates the user prompt with the branch's commits and diff
+func PRUserPrompt(base string, commits []string, diff string) string {
+	var list strings.Builder
+	for _, commit := range commits {
+		list.WriteString("- " + strings.ReplaceAll(commit, "\n", "\n  ") + "\n")
+	}
+
+	return fmt.Sprintf(`Write a pull request for merging this branch into %s.
+
+Commits, newest first:
+%s
+Changes:
+%s`, base, list.String(), diff)
+}
@@ -0,0 +1,36 @@
+package prompt
+
+import "fmt"
+
+// ReviewSystemPrompt generates the system prompt for code review
+func ReviewSystemPrompt() string {
+	return `You are a senior software engineer performing a careful code review of a git diff.
+Report real problems only: bugs, security issues, performance problems, error handling gaps,
+concurrency issues, maintainability concerns and style problems that matter.
+
+Respond with ONLY a JSON object in this exact shape, no other text:
+{"findings": [
+  {
+    "file": "path/to/file.go",
+    "line": 42,
+    "severity": "info|low|medium|high|critical",
+    "category": "bug|security|performance|error-handling|concurrency|maintainability|style|testing",
+    "message": "what is wrong and why it matters",
+    "suggestion": "how to fix it"
+  }
+]}
+
+Rules:
+1. "file" is the path from the diff header, "line" is the line number in the new version of the file
+2. Only comment on added or changed lines
+3. Use "critical" only for security holes, data loss or crashes
+4. Return {"findings": []} when there is nothing worth reporting
+5. Do not praise the code or summarise the change`
+}
+
+// ReviewUserPrompt generates the user prompt with the diff to review
+func ReviewUserPrompt(diff string) string {
+	return fmt.Sprintf(`Review the following git diff:
+
+%s`, diff)
+}
@@ -320,3 +320,15 @@ func TestProcessChunk_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestStyleHelpers(t *testing.T) {
+	for name, got := range map[string]string{
+		"Dim":   Dim("text"),
+		"Bold":  Bold("text"),
+		"Color": Color("text", "196"),
+	} {
+		if !strings.Contains(got, "text") {
+			t.Errorf("%s() = %q, should contain the original text", name, got)
+		}
+	}
+}
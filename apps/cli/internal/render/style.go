@@ -0,0 +1,18 @@
+package render
+
+import "github.com/charmbracelet/lipgloss"
+
+// Dim renders text in the muted style used for code block borders
+func Dim(text string) string {
+	return dimStyle.Render(text)
+}
+
+// Bold renders text in bold
+func Bold(text string) string {
+	return boldStyle.Render(text)
+}
+
+// Color renders text in bold with the given ANSI 256 color code
+func Color(text string, color string) string {
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color)).Render(text)
+}
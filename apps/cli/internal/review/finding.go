@@ -0,0 +1,159 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity ranks how serious a review finding is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "low", "medium", "high", "critical"}
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	if s < SeverityInfo || s > SeverityCritical {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity converts a severity name into a Severity
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, candidate := range severityNames {
+		if name == candidate {
+			return Severity(i), nil
+		}
+	}
+
+	// Accept common synonyms used by models
+	switch name {
+	case "warning", "warn", "moderate":
+		return SeverityMedium, nil
+	case "error", "major":
+		return SeverityHigh, nil
+	case "blocker":
+		return SeverityCritical, nil
+	case "minor", "nit", "style":
+		return SeverityLow, nil
+	}
+
+	return SeverityInfo, fmt.Errorf("unknown severity %q (use one of: %s)", name, strings.Join(severityNames, ", "))
+}
+
+// MarshalText encodes the severity as its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name
+// Names the model made up decode as info so one odd finding does not discard the whole review
+func (s *Severity) UnmarshalText(text []byte) error {
+	parsed, err := ParseSeverity(string(text))
+	if err != nil {
+		parsed = SeverityInfo
+	}
+	*s = parsed
+	return nil
+}
+
+// Finding is a single issue reported by the review
+type Finding struct {
+	File       string   `json:"file"`
+	Line       int      `json:"line,omitempty"`
+	Severity   Severity `json:"severity"`
+	Category   string   `json:"category"`
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// FileFindings groups the findings reported for one file
+type FileFindings struct {
+	File     string
+	Findings []Finding
+}
+
+// Parse extracts findings from the model output
+// The output is expected to contain a JSON object with a "findings" array, possibly wrapped in a code fence
+func Parse(output string) ([]Finding, error) {
+	data := extractJSON(output)
+	if data == "" {
+		return nil, fmt.Errorf("review response does not contain JSON")
+	}
+
+	var wrapped struct {
+		Findings []Finding `json:"findings"`
+	}
+	if strings.HasPrefix(data, "[") {
+		if err := json.Unmarshal([]byte(data), &wrapped.Findings); err != nil {
+			return nil, fmt.Errorf("failed to parse review findings: %w", err)
+		}
+	} else if err := json.Unmarshal([]byte(data), &wrapped); err != nil {
+		return nil, fmt.Errorf("failed to parse review findings: %w", err)
+	}
+
+	return wrapped.Findings, nil
+}
+
+// extractJSON returns the outermost JSON object or array in text
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return ""
+	}
+
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(text, closing)
+	if end < start {
+		return ""
+	}
+
+	return text[start : end+1]
+}
+
+// GroupByFile groups findings per file, sorted by file name and line
+func GroupByFile(findings []Finding) []FileFindings {
+	sorted := append([]Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	var groups []FileFindings
+	for _, finding := range sorted {
+		if len(groups) == 0 || groups[len(groups)-1].File != finding.File {
+			groups = append(groups, FileFindings{File: finding.File})
+		}
+		last := &groups[len(groups)-1]
+		last.Findings = append(last.Findings, finding)
+	}
+
+	return groups
+}
+
+// CountAtLeast returns how many findings are at or above the given severity
+func CountAtLeast(findings []Finding, threshold Severity) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity >= threshold {
+			count++
+		}
+	}
+	return count
+}
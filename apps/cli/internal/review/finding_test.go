@@ -0,0 +1,157 @@
+package review
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Severity
+		wantErr  bool
+	}{
+		{"info", SeverityInfo, false},
+		{"LOW", SeverityLow, false},
+		{" medium ", SeverityMedium, false},
+		{"high", SeverityHigh, false},
+		{"critical", SeverityCritical, false},
+		{"warning", SeverityMedium, false},
+		{"error", SeverityHigh, false},
+		{"nit", SeverityLow, false},
+		{"whatever", SeverityInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSeverity(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseSeverity(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	if SeverityHigh.String() != "high" {
+		t.Errorf("SeverityHigh.String() = %q, want high", SeverityHigh.String())
+	}
+	if Severity(42).String() != "severity(42)" {
+		t.Errorf("Severity(42).String() = %q", Severity(42).String())
+	}
+}
+
+func TestFinding_JSONRoundTrip(t *testing.T) {
+	finding := Finding{File: "main.go", Line: 10, Severity: SeverityHigh, Category: "bug", Message: "nil dereference"}
+
+	data, err := json.Marshal(finding)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded Finding
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded != finding {
+		t.Errorf("round trip = %+v, want %+v", decoded, finding)
+	}
+}
+
+func TestParse(t *testing.T) {
+	output := "Here is the review:\n```json\n" + `{"findings":[
+  {"file":"a.go","line":3,"severity":"high","category":"bug","message":"m1","suggestion":"s1"},
+  {"file":"b.go","line":1,"severity":"low","category":"style","message":"m2"}
+]}` + "\n```"
+
+	findings, err := Parse(output)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("Parse() returned %d findings, want 2", len(findings))
+	}
+	if findings[0].Severity != SeverityHigh || findings[0].Suggestion != "s1" {
+		t.Errorf("Parse()[0] = %+v", findings[0])
+	}
+}
+
+func TestParse_BareArray(t *testing.T) {
+	findings, err := Parse(`[{"file":"a.go","severity":"medium","message":"m"}]`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Severity != SeverityMedium {
+		t.Errorf("Parse() = %+v", findings)
+	}
+}
+
+func TestParse_NoFindings(t *testing.T) {
+	findings, err := Parse(`{"findings":[]}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("Parse() returned %d findings, want 0", len(findings))
+	}
+}
+
+func TestParse_UnknownSeverity(t *testing.T) {
+	findings, err := Parse(`{"findings":[
+  {"file":"a.go","severity":"bogus","message":"m1"},
+  {"file":"b.go","severity":"high","message":"m2"}
+]}`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(findings) != 2 || findings[0].Severity != SeverityInfo || findings[1].Severity != SeverityHigh {
+		t.Errorf("Parse() = %+v, want the unknown severity as info and the rest kept", findings)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"no json here", `{"findings": [`} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) should fail", input)
+		}
+	}
+}
+
+func TestGroupByFile(t *testing.T) {
+	findings := []Finding{
+		{File: "b.go", Line: 5},
+		{File: "a.go", Line: 9},
+		{File: "b.go", Line: 1},
+		{File: "a.go", Line: 2},
+	}
+
+	groups := GroupByFile(findings)
+
+	if len(groups) != 2 {
+		t.Fatalf("GroupByFile() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].File != "a.go" || groups[1].File != "b.go" {
+		t.Errorf("GroupByFile() order = %s, %s", groups[0].File, groups[1].File)
+	}
+	if groups[0].Findings[0].Line != 2 || groups[1].Findings[0].Line != 1 {
+		t.Error("GroupByFile() should sort findings by line")
+	}
+}
+
+func TestCountAtLeast(t *testing.T) {
+	findings := []Finding{
+		{Severity: SeverityLow},
+		{Severity: SeverityHigh},
+		{Severity: SeverityCritical},
+	}
+
+	if got := CountAtLeast(findings, SeverityHigh); got != 2 {
+		t.Errorf("CountAtLeast(high) = %d, want 2", got)
+	}
+	if got := CountAtLeast(findings, SeverityInfo); got != 3 {
+		t.Errorf("CountAtLeast(info) = %d, want 3", got)
+	}
+}
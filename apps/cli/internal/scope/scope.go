@@ -0,0 +1,85 @@
+package scope
+
+import (
+	"cmp"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// manifests mark the root directory of a package
+var manifests = []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "setup.py", "pom.xml", "build.gradle", "build.gradle.kts", "composer.json", "mix.exs", "pubspec.yaml"}
+
+// WorkspaceDirs are top-level directories whose children are packages by convention
+var WorkspaceDirs = []string{"apps", "packages", "services", "libs", "modules", "crates", "plugins", "tools"}
+
+// Area is a scope touched by a change and how many of the changed files belong to it
+type Area struct {
+	Scope string
+	Files int
+	Dir   string // Directory the scope was derived from
+}
+
+// Infer maps changed paths, relative to the repository root, to scopes
+// A configured mapping from path prefix to scope wins; otherwise the nearest package directory
+// below the root (one with a manifest such as go.mod or package.json) or a workspace directory
+// such as apps/<name> names the scope. Areas are returned with the most changed files first
+func Infer(root string, paths []string, mapping map[string]string) []Area {
+	areas := map[string]*Area{}
+	var order []string
+
+	for _, p := range paths {
+		scope, dir := mapped(p, mapping)
+		if scope == "" {
+			scope, dir = detect(root, p)
+		}
+		if scope == "" {
+			continue
+		}
+
+		if areas[scope] == nil {
+			areas[scope] = &Area{Scope: scope, Dir: dir}
+			order = append(order, scope)
+		}
+		areas[scope].Files++
+	}
+
+	result := make([]Area, 0, len(order))
+	for _, scope := range order {
+		result = append(result, *areas[scope])
+	}
+	slices.SortStableFunc(result, func(a, b Area) int { return cmp.Compare(b.Files, a.Files) })
+	return result
+}
+
+// mapped returns the scope of the longest configured prefix that contains p
+func mapped(p string, mapping map[string]string) (string, string) {
+	best, bestScope := "", ""
+	for prefix, scope := range mapping {
+		prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "/**"), "/")
+		if (p == prefix || strings.HasPrefix(p, prefix+"/")) && len(prefix) > len(best) {
+			best, bestScope = prefix, scope
+		}
+	}
+	return bestScope, best
+}
+
+// detect derives a scope from the package directory containing p
+func detect(root, p string) (string, string) {
+	// The nearest directory with a manifest, not counting the repository root itself
+	for dir := path.Dir(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		for _, manifest := range manifests {
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir), manifest)); err == nil {
+				return path.Base(dir), dir
+			}
+		}
+	}
+
+	parts := strings.Split(p, "/")
+	if len(parts) > 2 && slices.Contains(WorkspaceDirs, parts[0]) {
+		return parts[1], parts[0] + "/" + parts[1]
+	}
+	return "", ""
+}
@@ -0,0 +1,64 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"apps/cli", "apps/web", "lib/parser"} {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(root, "apps/cli/go.mod"), []byte("module cli\n"), 0644)
+	os.WriteFile(filepath.Join(root, "apps/web/package.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(root, "lib/parser/Cargo.toml"), []byte(""), 0644)
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module root\n"), 0644)
+
+	tests := []struct {
+		name    string
+		paths   []string
+		mapping map[string]string
+		want    []Area
+	}{
+		{
+			name:  "manifests",
+			paths: []string{"apps/cli/cmd/main.go", "apps/web/src/app.ts", "apps/cli/go.sum", "lib/parser/src/lib.rs"},
+			want: []Area{
+				{Scope: "cli", Files: 2, Dir: "apps/cli"},
+				{Scope: "web", Files: 1, Dir: "apps/web"},
+				{Scope: "parser", Files: 1, Dir: "lib/parser"},
+			},
+		},
+		{
+			name:  "workspace directory without manifest",
+			paths: []string{"apps/server/main.py"},
+			want:  []Area{{Scope: "server", Files: 1, Dir: "apps/server"}},
+		},
+		{
+			name:  "root files have no scope",
+			paths: []string{"README.md", "docs/intro.md"},
+			want:  []Area{},
+		},
+		{
+			name:    "mapping wins, longest prefix first",
+			paths:   []string{"apps/cli/internal/ai/client.go", "apps/cli/cmd/main.go", "docs/cli/README.md"},
+			mapping: map[string]string{"apps/cli": "cli", "apps/cli/internal/ai": "ai", "docs/**": "docs"},
+			want: []Area{
+				{Scope: "ai", Files: 1, Dir: "apps/cli/internal/ai"},
+				{Scope: "cli", Files: 1, Dir: "apps/cli"},
+				{Scope: "docs", Files: 1, Dir: "docs"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Infer(root, tt.paths, tt.mapping); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Infer() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
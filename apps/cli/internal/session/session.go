@@ -0,0 +1,145 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+)
+
+// titleLength is the maximum length of a generated session title in characters
+const titleLength = 60
+
+// Session represents a persisted chat conversation
+type Session struct {
+	ID        string       `json:"id"`
+	Title     string       `json:"title"`
+	Model     string       `json:"model"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Messages  []ai.Message `json:"messages"`
+
+	// The model only sees the messages from ContextStart on, after Summary of the earlier ones
+	Summary      string `json:"summary,omitempty"`
+	ContextStart int    `json:"context_start,omitempty"`
+}
+
+// New creates an empty session with a fresh ID
+func New(model string) *Session {
+	now := time.Now()
+	return &Session{
+		ID:        newID(now),
+		Model:     model,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// newID builds a sortable, human-readable session ID
+func newID(now time.Time) string {
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return now.Format("20060102-150405")
+	}
+	return now.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Exchanges returns the number of user messages in the session
+func (s *Session) Exchanges() int {
+	count := 0
+	for _, msg := range s.Messages {
+		if msg.Role == "user" {
+			count++
+		}
+	}
+	return count
+}
+
+// DeriveTitle returns the session title, falling back to the first user message
+func (s *Session) DeriveTitle() string {
+	if s.Title != "" {
+		return s.Title
+	}
+
+	for _, msg := range s.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		// Cut by runes so that multi-byte characters are never split
+		title := []rune(strings.Join(strings.Fields(msg.Content), " "))
+		if len(title) > titleLength {
+			return strings.TrimSpace(string(title[:titleLength-3])) + "..."
+		}
+		return string(title)
+	}
+
+	return "(empty)"
+}
+
+// Trim keeps the leading system prompt and at most limit of the most recent messages
+func Trim(messages []ai.Message, limit int) []ai.Message {
+	if limit <= 0 || len(messages) == 0 {
+		return messages
+	}
+
+	var system []ai.Message
+	rest := messages
+	if messages[0].Role == "system" {
+		system, rest = messages[:1], messages[1:]
+	}
+
+	if len(rest) <= limit {
+		return messages
+	}
+
+	trimmed := make([]ai.Message, 0, len(system)+limit)
+	trimmed = append(trimmed, system...)
+	return append(trimmed, rest[len(rest)-limit:]...)
+}
+
+// TrimMessages trims the messages to limit like Trim, keeping ContextStart on the same message
+func (s *Session) TrimMessages(limit int) {
+	before := len(s.Messages)
+	s.Messages = Trim(s.Messages, limit)
+	s.ContextStart = max(s.ContextStart-(before-len(s.Messages)), 0)
+}
+
+// ExportMarkdown renders the conversation as a Markdown document
+func ExportMarkdown(s *Session) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", s.DeriveTitle())
+	fmt.Fprintf(&b, "- Session: `%s`\n", s.ID)
+	fmt.Fprintf(&b, "- Model: `%s`\n", s.Model)
+	fmt.Fprintf(&b, "- Created: %s\n", s.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Updated: %s\n", s.UpdatedAt.Format(time.RFC3339))
+
+	for _, msg := range s.Messages {
+		switch msg.Role {
+		case "user":
+			b.WriteString("\n## You\n\n")
+		case "assistant":
+			b.WriteString("\n## Assistant\n\n")
+		default:
+			// System prompts are an implementation detail of the CLI
+			continue
+		}
+		b.WriteString(strings.TrimSpace(msg.Content))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// ExportJSON encodes the session as indented JSON
+func ExportJSON(s *Session) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,215 @@
+package session
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+)
+
+func TestNew(t *testing.T) {
+	sess := New("test-model")
+
+	if sess.ID == "" {
+		t.Error("New() returned session with empty ID")
+	}
+	if sess.Model != "test-model" {
+		t.Errorf("New() Model = %v, want test-model", sess.Model)
+	}
+	if sess.CreatedAt.IsZero() || sess.UpdatedAt.IsZero() {
+		t.Error("New() should set timestamps")
+	}
+
+	other := New("test-model")
+	if other.ID == sess.ID {
+		t.Errorf("New() generated duplicate ID %v", sess.ID)
+	}
+}
+
+func TestDeriveTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  *Session
+		expected string
+	}{
+		{
+			name:     "explicit title",
+			session:  &Session{Title: "My title"},
+			expected: "My title",
+		},
+		{
+			name: "first user message",
+			session: &Session{Messages: []ai.Message{
+				{Role: "system", Content: "system prompt"},
+				{Role: "user", Content: "how do\n  channels work?"},
+			}},
+			expected: "how do channels work?",
+		},
+		{
+			name:     "empty session",
+			session:  &Session{},
+			expected: "(empty)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.DeriveTitle(); got != tt.expected {
+				t.Errorf("DeriveTitle() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeriveTitle_Truncates(t *testing.T) {
+	sess := &Session{Messages: []ai.Message{
+		{Role: "user", Content: strings.Repeat("word ", 40)},
+	}}
+
+	title := sess.DeriveTitle()
+	if len(title) > titleLength {
+		t.Errorf("DeriveTitle() length = %d, want <= %d", len(title), titleLength)
+	}
+	if !strings.HasSuffix(title, "...") {
+		t.Errorf("DeriveTitle() = %q, want ellipsis suffix", title)
+	}
+}
+
+func TestDeriveTitle_TruncatesRunes(t *testing.T) {
+	sess := &Session{Messages: []ai.Message{
+		{Role: "user", Content: strings.Repeat("как работают каналы ", 10)},
+	}}
+
+	title := sess.DeriveTitle()
+	if !utf8.ValidString(title) {
+		t.Errorf("DeriveTitle() = %q, want valid UTF-8", title)
+	}
+	if n := utf8.RuneCountInString(title); n > titleLength {
+		t.Errorf("DeriveTitle() length = %d runes, want <= %d", n, titleLength)
+	}
+	if !strings.HasPrefix(title, "как работают каналы") || !strings.HasSuffix(title, "...") {
+		t.Errorf("DeriveTitle() = %q, want the start of the message and an ellipsis", title)
+	}
+}
+
+func TestExchanges(t *testing.T) {
+	sess := &Session{Messages: []ai.Message{
+		{Role: "system", Content: "s"},
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+	}}
+
+	if got := sess.Exchanges(); got != 2 {
+		t.Errorf("Exchanges() = %d, want 2", got)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "system", Content: "s"},
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+		{Role: "assistant", Content: "a2"},
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected []string
+	}{
+		{"no limit", 0, []string{"s", "q1", "a1", "q2", "a2"}},
+		{"under limit", 10, []string{"s", "q1", "a1", "q2", "a2"}},
+		{"keeps system prompt", 2, []string{"s", "q2", "a2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Trim(messages, tt.limit)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Trim() returned %d messages, want %d", len(got), len(tt.expected))
+			}
+			for i, content := range tt.expected {
+				if got[i].Content != content {
+					t.Errorf("Trim()[%d] = %q, want %q", i, got[i].Content, content)
+				}
+			}
+		})
+	}
+}
+
+func TestTrim_WithoutSystemPrompt(t *testing.T) {
+	messages := []ai.Message{
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+	}
+
+	got := Trim(messages, 1)
+	if len(got) != 1 || got[0].Content != "q2" {
+		t.Errorf("Trim() = %v, want only q2", got)
+	}
+}
+
+func TestTrimMessages_KeepsContextStart(t *testing.T) {
+	sess := New("test-model")
+	sess.Messages = []ai.Message{
+		{Role: "system", Content: "s"},
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+		{Role: "assistant", Content: "a2"},
+	}
+	sess.ContextStart = 3
+
+	sess.TrimMessages(3)
+	if len(sess.Messages) != 4 || sess.Messages[sess.ContextStart].Content != "q2" {
+		t.Errorf("TrimMessages() left %d messages starting the context at %d, want 4 and q2", len(sess.Messages), sess.ContextStart)
+	}
+
+	sess.TrimMessages(1)
+	if sess.ContextStart != 0 {
+		t.Errorf("TrimMessages() ContextStart = %d, want 0 once the start is trimmed away", sess.ContextStart)
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	sess := New("test-model")
+	sess.Messages = []ai.Message{
+		{Role: "system", Content: "secret system prompt"},
+		{Role: "user", Content: "What is Go?"},
+		{Role: "assistant", Content: "A programming language."},
+	}
+
+	md := ExportMarkdown(sess)
+
+	for _, want := range []string{"# What is Go?", "## You", "## Assistant", "A programming language.", sess.ID} {
+		if !strings.Contains(md, want) {
+			t.Errorf("ExportMarkdown() missing %q", want)
+		}
+	}
+	if strings.Contains(md, "secret system prompt") {
+		t.Error("ExportMarkdown() should not include the system prompt")
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	sess := New("test-model")
+	sess.Messages = []ai.Message{{Role: "user", Content: "hi"}}
+
+	data, err := ExportJSON(sess)
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+
+	var decoded Session
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("ExportJSON() produced invalid JSON: %v", err)
+	}
+	if decoded.ID != sess.ID || len(decoded.Messages) != 1 {
+		t.Errorf("ExportJSON() round trip = %+v, want %+v", decoded, sess)
+	}
+}
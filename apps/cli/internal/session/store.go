@@ -0,0 +1,167 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// ErrNotFound is returned when no session matches the requested ID
+var ErrNotFound = errors.New("session not found")
+
+// Store persists chat sessions as JSON files in a directory
+type Store struct {
+	dir   string
+	limit int
+}
+
+// NewStore creates a store in dir that keeps at most limit messages per session
+// A limit of zero or less keeps the whole conversation
+func NewStore(dir string, limit int) *Store {
+	return &Store{dir: dir, limit: limit}
+}
+
+// DefaultDir returns the sessions directory inside the ZIK config directory
+func DefaultDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sessions"), nil
+}
+
+// Save writes the session to disk, trimming it to the store's history limit
+func (s *Store) Save(sess *Session) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+
+	sess.TrimMessages(s.limit)
+	sess.Title = sess.DeriveTitle()
+	sess.UpdatedAt = time.Now()
+
+	data, err := ExportJSON(sess)
+	if err != nil {
+		return err
+	}
+
+	// Write through a temp file so an interrupted save never corrupts a session
+	tmpPath := s.path(sess.ID) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path(sess.ID)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a session by its ID or a unique ID prefix
+func (s *Store) Load(id string) (*Session, error) {
+	id, err := s.resolve(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.read(s.path(id))
+}
+
+// Delete removes a session by its ID or a unique ID prefix
+func (s *Store) Delete(id string) error {
+	id, err := s.resolve(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(id)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// List returns all stored sessions, most recently updated first
+func (s *Store) List() ([]*Session, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]*Session, 0, len(paths))
+	for _, path := range paths {
+		sess, err := s.read(path)
+		if err != nil {
+			// Skip unreadable files instead of hiding every other session
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+// Latest returns the most recently updated session
+func (s *Store) Latest() (*Session, error) {
+	sessions, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrNotFound
+	}
+	return sessions[0], nil
+}
+
+// resolve expands an ID prefix into a full session ID
+func (s *Store) resolve(prefix string) (string, error) {
+	if prefix == "" || strings.ContainsAny(prefix, `/\*?[`) {
+		return "", fmt.Errorf("invalid session ID %q", prefix)
+	}
+
+	if _, err := os.Stat(s.path(prefix)); err == nil {
+		return prefix, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(s.dir, prefix+"*.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up session: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, prefix)
+	case 1:
+		return strings.TrimSuffix(filepath.Base(matches[0]), ".json"), nil
+	default:
+		return "", fmt.Errorf("session ID %q is ambiguous (%d matches)", prefix, len(matches))
+	}
+}
+
+// read decodes a session file
+func (s *Store) read(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", filepath.Base(path), err)
+	}
+	return &sess, nil
+}
+
+// path returns the file path for a session ID
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	sess := New("test-model")
+	sess.Messages = []ai.Message{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "hi there"},
+	}
+
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load(sess.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if loaded.Title != "hello" {
+		t.Errorf("Load() Title = %q, want hello", loaded.Title)
+	}
+	if len(loaded.Messages) != 2 {
+		t.Errorf("Load() returned %d messages, want 2", len(loaded.Messages))
+	}
+}
+
+func TestStore_SaveTrimsToLimit(t *testing.T) {
+	store := NewStore(t.TempDir(), 2)
+
+	sess := New("test-model")
+	sess.Messages = []ai.Message{
+		{Role: "system", Content: "s"},
+		{Role: "user", Content: "q1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "q2"},
+		{Role: "assistant", Content: "a2"},
+	}
+
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load(sess.ID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(loaded.Messages) != 3 {
+		t.Errorf("Load() returned %d messages, want 3 (system + limit)", len(loaded.Messages))
+	}
+}
+
+func TestStore_LoadByPrefix(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	sess := &Session{ID: "20260101-120000-abcd"}
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load("20260101-120000")
+	if err != nil {
+		t.Fatalf("Load() by prefix error = %v", err)
+	}
+	if loaded.ID != sess.ID {
+		t.Errorf("Load() ID = %v, want %v", loaded.ID, sess.ID)
+	}
+}
+
+func TestStore_LoadAmbiguousPrefix(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	for _, id := range []string{"20260101-120000-aaaa", "20260101-120000-bbbb"} {
+		if err := store.Save(&Session{ID: id}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if _, err := store.Load("20260101"); err == nil {
+		t.Error("Load() should fail for ambiguous prefix")
+	}
+}
+
+func TestStore_LoadNotFound(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	_, err := store.Load("missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStore_LoadInvalidID(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	for _, id := range []string{"", "../config", "*"} {
+		if _, err := store.Load(id); err == nil {
+			t.Errorf("Load(%q) should fail", id)
+		}
+	}
+}
+
+func TestStore_ListAndLatest(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 0)
+
+	if _, err := store.Latest(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Latest() on empty store error = %v, want ErrNotFound", err)
+	}
+
+	first := &Session{ID: "first"}
+	second := &Session{ID: "second"}
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := store.Save(second); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Corrupt files must not break listing
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatalf("failed to write broken session: %v", err)
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("List() returned %d sessions, want 2", len(sessions))
+	}
+	if sessions[0].ID != "second" {
+		t.Errorf("List()[0] = %v, want most recent session first", sessions[0].ID)
+	}
+
+	latest, err := store.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if latest.ID != "second" {
+		t.Errorf("Latest() = %v, want second", latest.ID)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := NewStore(t.TempDir(), 0)
+
+	sess := &Session{ID: "to-delete"}
+	if err := store.Save(sess); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := store.Delete("to-delete"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := store.Load("to-delete"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
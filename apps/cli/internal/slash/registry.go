@@ -0,0 +1,115 @@
+package slash
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Prefix marks a line of input as a slash command
+const Prefix = "/"
+
+// ErrUnknownCommand is returned when a slash command is not registered
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Handler runs a slash command with its raw argument string
+type Handler func(args string) error
+
+// Command describes a single slash command
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Run         Handler
+}
+
+// Registry holds the available slash commands
+type Registry struct {
+	commands map[string]*Command
+	order    []string
+}
+
+// NewRegistry creates an empty command registry
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]*Command)}
+}
+
+// Register adds a command to the registry, replacing any command with the same name
+func (r *Registry) Register(cmd Command) {
+	if _, exists := r.commands[cmd.Name]; !exists {
+		r.order = append(r.order, cmd.Name)
+	}
+	if cmd.Usage == "" {
+		cmd.Usage = Prefix + cmd.Name
+	}
+	r.commands[cmd.Name] = &cmd
+}
+
+// Lookup returns the command registered under name
+func (r *Registry) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Commands returns all commands in registration order
+func (r *Registry) Commands() []*Command {
+	cmds := make([]*Command, 0, len(r.order))
+	for _, name := range r.order {
+		cmds = append(cmds, r.commands[name])
+	}
+	return cmds
+}
+
+// Dispatch runs the command in line if it is a slash command
+// It reports whether the line was handled as a command
+func (r *Registry) Dispatch(line string) (bool, error) {
+	name, args, ok := Parse(line)
+	if !ok {
+		return false, nil
+	}
+
+	cmd, found := r.Lookup(name)
+	if !found {
+		return true, fmt.Errorf("%w: %s%s (try %shelp)", ErrUnknownCommand, Prefix, name, Prefix)
+	}
+
+	return true, cmd.Run(args)
+}
+
+// Help returns a formatted list of all commands
+func (r *Registry) Help() string {
+	cmds := r.Commands()
+
+	width := 0
+	for _, cmd := range cmds {
+		if len(cmd.Usage) > width {
+			width = len(cmd.Usage)
+		}
+	}
+
+	var b strings.Builder
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, cmd.Usage, cmd.Description)
+	}
+	return b.String()
+}
+
+// Parse splits a slash command line into its name and argument string
+func Parse(line string) (name, args string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, Prefix) {
+		return "", "", false
+	}
+
+	line = strings.TrimPrefix(line, Prefix)
+	name = line
+	if idx := strings.IndexFunc(line, unicode.IsSpace); idx >= 0 {
+		name, args = line[:idx], strings.TrimSpace(line[idx:])
+	}
+	if name == "" {
+		return "", "", false
+	}
+
+	return strings.ToLower(name), args, true
+}
@@ -0,0 +1,117 @@
+package slash
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantName string
+		wantArgs string
+		wantOK   bool
+	}{
+		{"/help", "help", "", true},
+		{"  /model gpt-4  ", "model", "gpt-4", true},
+		{"/system You are a pirate.", "system", "You are a pirate.", true},
+		{"/file\tmain.go", "file", "main.go", true},
+		{"/MODEL x", "model", "x", true},
+		{"hello", "", "", false},
+		{"/", "", "", false},
+		{"/ help", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			name, args, ok := Parse(tt.input)
+			if name != tt.wantName || args != tt.wantArgs || ok != tt.wantOK {
+				t.Errorf("Parse(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.input, name, args, ok, tt.wantName, tt.wantArgs, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRegistry_Dispatch(t *testing.T) {
+	registry := NewRegistry()
+
+	var gotArgs string
+	registry.Register(Command{
+		Name: "echo",
+		Run: func(args string) error {
+			gotArgs = args
+			return nil
+		},
+	})
+
+	handled, err := registry.Dispatch("/echo hello world")
+	if !handled || err != nil {
+		t.Fatalf("Dispatch() = (%v, %v), want (true, nil)", handled, err)
+	}
+	if gotArgs != "hello world" {
+		t.Errorf("handler args = %q, want %q", gotArgs, "hello world")
+	}
+}
+
+func TestRegistry_DispatchPlainText(t *testing.T) {
+	registry := NewRegistry()
+
+	handled, err := registry.Dispatch("just a question")
+	if handled || err != nil {
+		t.Errorf("Dispatch() = (%v, %v), want (false, nil) for plain text", handled, err)
+	}
+}
+
+func TestRegistry_DispatchUnknown(t *testing.T) {
+	registry := NewRegistry()
+
+	handled, err := registry.Dispatch("/nope")
+	if !handled {
+		t.Error("Dispatch() should report unknown commands as handled")
+	}
+	if !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("Dispatch() error = %v, want ErrUnknownCommand", err)
+	}
+}
+
+func TestRegistry_DispatchHandlerError(t *testing.T) {
+	registry := NewRegistry()
+	wantErr := errors.New("boom")
+	registry.Register(Command{Name: "fail", Run: func(string) error { return wantErr }})
+
+	_, err := registry.Dispatch("/fail")
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Dispatch() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestRegistry_RegisterReplaces(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Command{Name: "a", Description: "first"})
+	registry.Register(Command{Name: "b"})
+	registry.Register(Command{Name: "a", Description: "second"})
+
+	cmds := registry.Commands()
+	if len(cmds) != 2 {
+		t.Fatalf("Commands() returned %d commands, want 2", len(cmds))
+	}
+	if cmds[0].Name != "a" || cmds[0].Description != "second" {
+		t.Errorf("Commands()[0] = %+v, want replaced command a in original position", cmds[0])
+	}
+}
+
+func TestRegistry_Help(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Command{Name: "model", Usage: "/model [name]", Description: "Show or switch the model"})
+	registry.Register(Command{Name: "help", Description: "Show this help"})
+
+	help := registry.Help()
+
+	for _, want := range []string{"/model [name]", "Show or switch the model", "/help", "Show this help"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("Help() missing %q:\n%s", want, help)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/tokens"
+)
+
+// Chunk is a contiguous block of source lines
+type Chunk struct {
+	Start int // First line number, 1-based
+	End   int // Last line number, inclusive
+	Lines []string
+}
+
+// Text returns the chunk lines joined with newlines
+func (c Chunk) Text() string {
+	return strings.Join(c.Lines, "\n")
+}
+
+// Numbered returns the chunk lines prefixed with their line numbers
+func (c Chunk) Numbered() string {
+	width := len(fmt.Sprint(c.End))
+
+	var b strings.Builder
+	for i, line := range c.Lines {
+		fmt.Fprintf(&b, "%*d | %s\n", width, c.Start+i, line)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Split divides lines into chunks of at most maxTokens estimated tokens each
+// first is the line number of lines[0]; a single line larger than the budget forms its own chunk
+func Split(lines []string, first, maxTokens int) []Chunk {
+	if len(lines) == 0 {
+		return nil
+	}
+	if maxTokens <= 0 {
+		return []Chunk{{Start: first, End: first + len(lines) - 1, Lines: lines}}
+	}
+
+	var chunks []Chunk
+	current := Chunk{Start: first}
+	used := 0
+
+	for i, line := range lines {
+		// Account for the line number prefix and newline added when the chunk is sent
+		cost := tokens.Estimate(line) + 2
+		if used+cost > maxTokens && len(current.Lines) > 0 {
+			chunks = append(chunks, current)
+			current = Chunk{Start: first + i}
+			used = 0
+		}
+		current.Lines = append(current.Lines, line)
+		current.End = first + i
+		used += cost
+	}
+
+	return append(chunks, current)
+}
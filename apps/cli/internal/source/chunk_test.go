@@ -0,0 +1,74 @@
+package source
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplit_FitsInOneChunk(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+
+	chunks := Split(lines, 10, 1000)
+
+	if len(chunks) != 1 {
+		t.Fatalf("Split() returned %d chunks, want 1", len(chunks))
+	}
+	if chunks[0].Start != 10 || chunks[0].End != 12 {
+		t.Errorf("Split() chunk = %d-%d, want 10-12", chunks[0].Start, chunks[0].End)
+	}
+}
+
+func TestSplit_MultipleChunks(t *testing.T) {
+	// Each line costs 3 tokens (1 content + 2 overhead)
+	lines := []string{"aaaa", "bbbb", "cccc", "dddd", "eeee"}
+
+	chunks := Split(lines, 1, 6)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Split() returned %d chunks, want 3", len(chunks))
+	}
+
+	covered := 0
+	next := 1
+	for _, chunk := range chunks {
+		if chunk.Start != next {
+			t.Errorf("chunk starts at %d, want %d", chunk.Start, next)
+		}
+		if chunk.End-chunk.Start+1 != len(chunk.Lines) {
+			t.Errorf("chunk %d-%d has %d lines", chunk.Start, chunk.End, len(chunk.Lines))
+		}
+		covered += len(chunk.Lines)
+		next = chunk.End + 1
+	}
+	if covered != len(lines) {
+		t.Errorf("chunks cover %d lines, want %d", covered, len(lines))
+	}
+}
+
+func TestSplit_OversizedLine(t *testing.T) {
+	lines := []string{"short", strings.Repeat("x", 400), "short"}
+
+	chunks := Split(lines, 1, 10)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Split() returned %d chunks, want 3", len(chunks))
+	}
+}
+
+func TestSplit_Empty(t *testing.T) {
+	if chunks := Split(nil, 1, 10); chunks != nil {
+		t.Errorf("Split(nil) = %v, want nil", chunks)
+	}
+}
+
+func TestChunk_Numbered(t *testing.T) {
+	chunk := Chunk{Start: 9, End: 10, Lines: []string{"foo", "bar"}}
+
+	expected := " 9 | foo\n10 | bar"
+	if got := chunk.Numbered(); got != expected {
+		t.Errorf("Numbered() = %q, want %q", got, expected)
+	}
+	if got := chunk.Text(); got != "foo\nbar" {
+		t.Errorf("Text() = %q, want %q", got, "foo\nbar")
+	}
+}
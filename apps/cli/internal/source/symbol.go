@@ -0,0 +1,115 @@
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// FindSymbol locates a Go function, method, type, constant or variable declaration
+// Methods can be named either "Method" or "Type.Method"
+// It returns the inclusive line range of the declaration including its doc comment
+func FindSymbol(path string, src []byte, name string) (start, end int, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	receiver, symbol, hasReceiver := strings.Cut(name, ".")
+	if !hasReceiver {
+		symbol, receiver = name, ""
+	}
+
+	var matches []ast.Node
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Name.Name != symbol {
+				continue
+			}
+			if receiver != "" && receiverName(d) != receiver {
+				continue
+			}
+			matches = append(matches, d)
+		case *ast.GenDecl:
+			if receiver != "" {
+				continue
+			}
+			if genDeclDefines(d, symbol) {
+				matches = append(matches, d)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, 0, fmt.Errorf("symbol %q not found in %s", name, path)
+	case 1:
+	default:
+		return 0, 0, fmt.Errorf("symbol %q is ambiguous in %s, use Type.Method", name, path)
+	}
+
+	node := matches[0]
+	pos := node.Pos()
+	if doc := docComment(node); doc != nil {
+		pos = doc.Pos()
+	}
+
+	return fset.Position(pos).Line, fset.Position(node.End()).Line, nil
+}
+
+// receiverName returns the base type name of a method receiver
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := fn.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// genDeclDefines reports whether a type, const or var declaration defines name
+func genDeclDefines(decl *ast.GenDecl, name string) bool {
+	for _, spec := range decl.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if s.Name.Name == name {
+				return true
+			}
+		case *ast.ValueSpec:
+			for _, ident := range s.Names {
+				if ident.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// docComment returns the doc comment attached to a declaration
+func docComment(node ast.Node) *ast.CommentGroup {
+	switch d := node.(type) {
+	case *ast.FuncDecl:
+		return d.Doc
+	case *ast.GenDecl:
+		return d.Doc
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package source
+
+import "testing"
+
+const symbolSource = `package sample
+
+// Greeter says hello
+type Greeter struct {
+	Name string
+}
+
+// Greet returns a greeting
+func (g *Greeter) Greet() string {
+	return "hello " + g.Name
+}
+
+// Greet is a package-level greeting
+func Greet(name string) string {
+	return "hi " + name
+}
+
+const Answer = 42
+
+var (
+	First, Second = 1, 2
+)
+`
+
+func TestFindSymbol(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbol    string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{"type with doc", "Greeter", 3, 6, false},
+		{"method by receiver", "Greeter.Greet", 8, 11, false},
+		{"ambiguous function", "Greet", 0, 0, true},
+		{"constant", "Answer", 18, 18, false},
+		{"grouped variable", "Second", 20, 22, false},
+		{"missing", "Missing", 0, 0, true},
+		{"wrong receiver", "Other.Greet", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := FindSymbol("sample.go", []byte(symbolSource), tt.symbol)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindSymbol(%q) error = %v, wantErr %v", tt.symbol, err, tt.wantErr)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("FindSymbol(%q) = %d-%d, want %d-%d", tt.symbol, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFindSymbol_InvalidSource(t *testing.T) {
+	if _, _, err := FindSymbol("broken.go", []byte("not go code"), "X"); err == nil {
+		t.Error("FindSymbol() should fail on invalid Go source")
+	}
+}
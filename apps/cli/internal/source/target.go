@@ -0,0 +1,82 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Target identifies a file and an optional inclusive line range within it
+type Target struct {
+	Path  string
+	Start int // First line, 1-based; 0 means the start of the file
+	End   int // Last line, inclusive; 0 means the end of the file
+}
+
+// ParseTarget parses "file", "file:40" or "file:40-80"
+func ParseTarget(arg string) (Target, error) {
+	idx := strings.LastIndex(arg, ":")
+	if idx <= 0 {
+		return Target{Path: arg}, nil
+	}
+
+	path, spec := arg[:idx], arg[idx+1:]
+	startText, endText, isRange := strings.Cut(spec, "-")
+
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		// Not a line spec, the colon belongs to the path
+		if !isRange {
+			return Target{Path: arg}, nil
+		}
+		return Target{}, fmt.Errorf("invalid line range %q", spec)
+	}
+
+	end := start
+	if isRange {
+		end, err = strconv.Atoi(endText)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid line range %q", spec)
+		}
+	}
+
+	if start < 1 || end < start {
+		return Target{}, fmt.Errorf("invalid line range %q", spec)
+	}
+
+	return Target{Path: path, Start: start, End: end}, nil
+}
+
+// Lines reads the file and returns the lines selected by the target
+// The returned start is the 1-based number of the first returned line
+func (t Target) Lines() (lines []string, start int, err error) {
+	data, err := os.ReadFile(t.Path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+
+	all := SplitLines(string(data))
+	if t.Start == 0 {
+		return all, 1, nil
+	}
+
+	if t.Start > len(all) {
+		return nil, 0, fmt.Errorf("%s has only %d lines", t.Path, len(all))
+	}
+	end := t.End
+	if end == 0 || end > len(all) {
+		end = len(all)
+	}
+
+	return all[t.Start-1 : end], t.Start, nil
+}
+
+// SplitLines splits text into lines without a trailing empty line
+func SplitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
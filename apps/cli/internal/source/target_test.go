@@ -0,0 +1,77 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Target
+		wantErr bool
+	}{
+		{"main.go", Target{Path: "main.go"}, false},
+		{"main.go:40", Target{Path: "main.go", Start: 40, End: 40}, false},
+		{"main.go:40-80", Target{Path: "main.go", Start: 40, End: 80}, false},
+		{"dir/with:colon/file.go", Target{Path: "dir/with:colon/file.go"}, false},
+		{"main.go:80-40", Target{}, true},
+		{"main.go:0", Target{}, true},
+		{"main.go:a-b", Target{}, true},
+		{"main.go:10-x", Target{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTarget(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTarget(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTarget(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTarget_Lines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		target    Target
+		wantLines []string
+		wantStart int
+		wantErr   bool
+	}{
+		{"whole file", Target{Path: path}, []string{"one", "two", "three", "four"}, 1, false},
+		{"range", Target{Path: path, Start: 2, End: 3}, []string{"two", "three"}, 2, false},
+		{"range past end", Target{Path: path, Start: 3, End: 100}, []string{"three", "four"}, 3, false},
+		{"start past end", Target{Path: path, Start: 10, End: 12}, nil, 0, true},
+		{"missing file", Target{Path: path + ".missing"}, nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, start, err := tt.target.Lines()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if start != tt.wantStart {
+				t.Errorf("Lines() start = %d, want %d", start, tt.wantStart)
+			}
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("Lines() = %v, want %v", lines, tt.wantLines)
+			}
+			for i := range lines {
+				if lines[i] != tt.wantLines[i] {
+					t.Errorf("Lines()[%d] = %q, want %q", i, lines[i], tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package tokens
+
+import "unicode/utf8"
+
+// charsPerToken is the average number of ASCII characters per token for English text and code
+const charsPerToken = 4
+
+// Estimate returns an approximate token count for text
+// It is intentionally conservative: ASCII text is counted at four characters per token
+// and every non-ASCII rune as a full token, so real usage rarely exceeds the estimate
+func Estimate(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+
+	return (ascii+charsPerToken-1)/charsPerToken + other
+}
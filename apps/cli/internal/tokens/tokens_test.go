@@ -0,0 +1,38 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{"empty", "", 0},
+		{"single char", "a", 1},
+		{"four chars", "abcd", 1},
+		{"five chars", "abcde", 2},
+		{"non-ascii runes", "привет", 6},
+		{"mixed", "hi мир", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Estimate(tt.input); got != tt.expected {
+				t.Errorf("Estimate(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimate_Grows(t *testing.T) {
+	short := Estimate(strings.Repeat("token ", 10))
+	long := Estimate(strings.Repeat("token ", 100))
+
+	if long <= short {
+		t.Errorf("Estimate() of longer text = %d, want more than %d", long, short)
+	}
+}
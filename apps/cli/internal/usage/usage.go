@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+	"github.com/zarazaex69/zik/apps/cli/internal/config"
+)
+
+// Record is the token usage of a single AI request
+type Record struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"` // Command that made the request, e.g. "code review"
+	Model   string    `json:"model"`
+	ai.Usage
+}
+
+// Ledger is an append-only log of usage records, one JSON object per line
+type Ledger struct {
+	path string
+}
+
+// NewLedger creates a ledger stored in the file at path
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// DefaultPath returns the ledger file inside the ZIK config directory
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.jsonl"), nil
+}
+
+// Append adds a record to the end of the ledger
+func (l *Ledger) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+
+	// A single write keeps concurrent zik processes from interleaving lines
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write usage ledger: %w", err)
+	}
+	return nil
+}
+
+// Since returns the records made at or after t, oldest first
+// A missing ledger has no records, and malformed lines are skipped
+func (l *Ledger) Since(t time.Time) ([]Record, error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if !record.Time.Before(t) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+	return records, nil
+}
+
+// Total is the usage of a group of records
+type Total struct {
+	Key      string // Command or model shared by the records
+	Requests int
+	ai.Usage
+}
+
+// Add counts a request and its usage in the total
+func (t *Total) Add(usage ai.Usage) {
+	t.Requests++
+	t.PromptTokens += usage.PromptTokens
+	t.CompletionTokens += usage.CompletionTokens
+	t.TotalTokens += usage.TotalTokens
+}
+
+// Summarize groups records by "command" or "model", largest total first
+func Summarize(records []Record, by string) ([]Total, error) {
+	key := func(r Record) string { return r.Command }
+	switch by {
+	case "command":
+	case "model":
+		key = func(r Record) string { return r.Model }
+	default:
+		return nil, fmt.Errorf("invalid grouping %q (must be command or model)", by)
+	}
+
+	totals := map[string]*Total{}
+	for _, record := range records {
+		k := key(record)
+		if totals[k] == nil {
+			totals[k] = &Total{Key: k}
+		}
+		totals[k].Add(record.Usage)
+	}
+
+	result := make([]Total, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalTokens != result[j].TotalTokens {
+			return result[i].TotalTokens > result[j].TotalTokens
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// ParseSince reads the start of a reporting period relative to now
+// It accepts days and weeks ("7d", "2w"), Go durations ("12h") and dates ("2024-05-01")
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if n := len(value); n > 1 && units[value[n-1]] > 0 {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			return now.Add(-time.Duration(count) * units[value[n-1]]), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid period %q (use e.g. 7d, 2w, 12h or 2024-05-01)", value)
+}
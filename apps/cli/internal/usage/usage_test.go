@@ -0,0 +1,108 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zarazaex69/zik/apps/cli/internal/ai"
+)
+
+func TestLedger_AppendSince(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zik", "usage.jsonl")
+	ledger := NewLedger(path)
+
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	old := Record{Time: now.AddDate(0, 0, -10), Command: "ask", Model: "a", Usage: ai.Usage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2}}
+	recent := Record{Time: now.Add(-time.Hour), Command: "commit", Model: "b", Usage: ai.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}}
+
+	records, err := ledger.Since(now.AddDate(0, 0, -7))
+	if err != nil || records != nil {
+		t.Fatalf("Since() on a missing ledger = %v, %v; want no records", records, err)
+	}
+
+	for _, r := range []Record{old, recent} {
+		if err := ledger.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	// Lines that are not records are skipped
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	file.WriteString("{truncated\n")
+	file.Close()
+
+	records, err = ledger.Since(now.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(records) != 1 || !reflect.DeepEqual(records[0], recent) {
+		t.Errorf("Since() = %+v, want only %+v", records, recent)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{Command: "ask", Model: "small", Usage: ai.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		{Command: "commit", Model: "large", Usage: ai.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}},
+		{Command: "ask", Model: "large", Usage: ai.Usage{PromptTokens: 30, CompletionTokens: 10, TotalTokens: 40}},
+	}
+
+	tests := []struct {
+		by   string
+		want []Total
+	}{
+		{"command", []Total{
+			{Key: "commit", Requests: 1, Usage: ai.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}},
+			{Key: "ask", Requests: 2, Usage: ai.Usage{PromptTokens: 40, CompletionTokens: 15, TotalTokens: 55}},
+		}},
+		{"model", []Total{
+			{Key: "large", Requests: 2, Usage: ai.Usage{PromptTokens: 130, CompletionTokens: 30, TotalTokens: 160}},
+			{Key: "small", Requests: 1, Usage: ai.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		}},
+	}
+
+	for _, tt := range tests {
+		got, err := Summarize(records, tt.by)
+		if err != nil {
+			t.Fatalf("Summarize(%q) error = %v", tt.by, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Summarize(%q) = %+v, want %+v", tt.by, got, tt.want)
+		}
+	}
+
+	if _, err := Summarize(records, "day"); err == nil {
+		t.Error("Summarize(\"day\") error = nil, want invalid grouping")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2w", want: now.AddDate(0, 0, -14)},
+		{value: "12h", want: now.Add(-12 * time.Hour)},
+		{value: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "d", wantErr: true},
+		{value: "-3d", wantErr: true},
+		{value: "last week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}